@@ -108,6 +108,10 @@ func (c *Collection) Append(name string, funcs ...any) *Collection {
 // When providers are not named, they get their name from their position
 // in their collection combined with the name of the collection they are in.
 //
+// Providers also have a path: their name prefixed by the names of
+// the collections they are in, like "server/db/open".  The path is used
+// in error messages and Debugging.
+//
 // When used on an existing Provider, it creates an annotated copy of that provider.
 func Provide(name string, fn any) Provider {
 	return newThing(fn).modify(func(fm *provider) {
@@ -240,6 +244,23 @@ func Shun(fn any) Provider {
 	})
 }
 
+// Strict creates a new provider and annotates it as strict: if
+// it is excluded from the chain, for any reason, then the chain
+// is invalid.  Unlike Required, Strict does not force the provider
+// into the chain; instead the error from Bind lists the strict
+// providers that were dropped and why.  Use Strict where a provider
+// being dropped always indicates a mistake, like a mis-typed output.
+//
+// Strict can be applied to a Collection to make all of its members
+// strict.
+//
+// When used on an existing Provider, it creates an annotated copy of that provider.
+func Strict(fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		fm.strict = true
+	})
+}
+
 // TODO: add ExampleMustConsume
 
 // MustConsume creates a new provider and annotates it as
@@ -346,6 +367,11 @@ func Parallel(fn any) Provider {
 // implements the interface.
 //
 // By default, an exact match of types is required for all providers.
+//
+// When more than one type could fill an interface, the MatchPolicy
+// (see WithMatchPolicy) decides which one is used.  If there is a tie,
+// Bind fails, even if the provider that receives the interface is not
+// required.  BindInterface overrides both.
 func Loose[T any](fn any) Provider {
 	return newThing(fn).modify(func(fm *provider) {
 		if fm.loose == nil {
@@ -410,6 +436,34 @@ func AllowReturnShadowing[T any](fn any) Provider {
 	})
 }
 
+// Exclusive annotates a provider to say that it must be the only
+// provider of T in the chain: if any other included provider also
+// provides T, then the chain is invalid and the error from Bind names
+// both providers.  That includes when the Exclusive provider is only
+// excluded because another provider of T is closer to its consumers.
+// Without Exclusive, when there is more than one provider of a type,
+// the closest one is silently used.
+//
+// Providers that receive T and provide T (pass-through providers
+// that modify the value) are not considered to be other providers of T.
+//
+// Exclusive can be applied to a Collection.  It then applies to
+// the members of the collection that provide T.
+//
+// When used on an existing Provider, it creates an annotated copy of that provider.
+//
+// Exclusive may be called on the provider it returns creating a provider
+// that is exclusive for multiple types.
+func Exclusive[T any](fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		if fm.exclusive == nil {
+			fm.exclusive = make(map[typeCode]struct{})
+		}
+		t := reflect.TypeOf((*T)(nil)).Elem()
+		fm.exclusive[getTypeCode(t)] = struct{}{}
+	})
+}
+
 // Bind expects to receive two function pointers for functions
 // that are not yet defined.  Bind defines the functions.  The
 // first function is called to invoke the Collection of providers.
@@ -466,9 +520,9 @@ func (c *Collection) bindFast(invokeFunc any, initFunc any) error {
 		initF = newProvider(initFunc, -1, c.name+" initialization func")
 	}
 
-	debugLock.RLock()
-	defer debugLock.RUnlock()
-	return doBind(c, invokeF, initF, true)
+	tr := newBindTracer(c)
+	defer tr.doneBinding()
+	return doBind(c, invokeF, initF, true, tr, nil)
 }
 
 // SetCallback expects to receive a function as an argument.  SetCallback() will call
@@ -7,14 +7,21 @@ import (
 )
 
 // When !isReal, do not actually bind.  !isReal is used for generating debug traces.
-func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider, isReal bool) error {
+// If inspect is not nil, it is given the providers once inclusion has been decided.
+func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider, isReal bool, tr *tracer, inspect func([]*provider)) error {
 	// Split up the collection into LITERAL, STATIC, RUN, and FINAL groups. Add
 	// init and invoke as faked providers.  Flatten into one ordered list.
 	var invokeIndex int
 	var invokeF *provider
 	var initF *provider
 	var debuggingProvider **provider
+	originalSc := sc
+	sc = resolveOptionalInputs(sc, originalInvokeF, originalInitF, tr.inPhase(phaseReplace))
 	funcs := make([]*provider, 0, len(sc.contents)+5)
+	rules, err := newMatchRules(sc)
+	if err != nil {
+		return err
+	}
 	{
 		var err error
 		invokeF, err = characterizeInitInvoke(originalInvokeF, charContext{inputsAreStatic: false})
@@ -26,7 +33,18 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 			nonStaticTypes[tc] = true
 		}
 
-		beforeInvoke, afterInvoke, err := sc.characterizeAndFlatten(nonStaticTypes)
+		if originalInitF != nil {
+			initF, err = characterizeInitInvoke(originalInitF, charContext{inputsAreStatic: true})
+			if err != nil {
+				return err
+			}
+		}
+		var initTypes []typeCode
+		if initF != nil {
+			initTypes = initF.flows[outputParams]
+		}
+
+		beforeInvoke, afterInvoke, err := sc.characterizeAndFlatten(nonStaticTypes, initTypes, tr.inPhase(phaseCharacterize))
 		if err != nil {
 			return err
 		}
@@ -43,11 +61,7 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 		}
 
 		// Add init
-		if originalInitF != nil {
-			initF, err = characterizeInitInvoke(originalInitF, charContext{inputsAreStatic: true})
-			if err != nil {
-				return err
-			}
+		if initF != nil {
 			funcs = append(funcs, initF)
 		}
 
@@ -115,8 +129,7 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 
 	// Compute dependencies: set fm.downRmap, fm.upRmap, fm.cannotInclude,
 	// fm.whyIncluded, fm.include
-	var err error
-	funcs, err = computeDependenciesAndInclusion(funcs, initF)
+	funcs, err = computeDependenciesAndInclusion(funcs, initF, rules, tr.inPhase(phaseInclude))
 	if err != nil {
 		return err
 	}
@@ -126,6 +139,14 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 		return err
 	}
 
+	err = checkExclusive(funcs)
+	if err != nil {
+		return err
+	}
+	if inspect != nil {
+		inspect(funcs)
+	}
+
 	// Build the lists of parameters that are included in the value collections.
 	// These are maps from types to position in the value collection.
 	//
@@ -207,10 +228,11 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 			}
 
 			var trace string
-			if debugEnabled() {
+			if tr.capturing() {
+				// this bind is itself being captured
 				trace = "debugging already in progress"
 			} else {
-				trace = captureDoBindDebugging(sc, originalInvokeF, originalInitF)
+				trace = captureDoBindDebugging(originalSc, originalInvokeF, originalInitF)
 			}
 
 			reproduce := generateReproduce(funcs, invokeF, initF)
@@ -224,9 +246,9 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 			}
 		}
 	}
-	if debugEnabled() {
+	if tr.enabled() {
 		for _, fm := range funcs {
-			dumpF("funclist", fm)
+			tr.dumpF("funclist", fm)
 		}
 	}
 
@@ -236,7 +258,7 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 		if !fm.include {
 			continue
 		}
-		err := generateWrappers(fm, downVmap, upVmap)
+		err := generateWrappers(fm, downVmap, upVmap, tr.inPhase(phaseGenerate))
 		if err != nil {
 			return err
 		}
@@ -308,13 +330,13 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 
 	// Generate static chain function
 	runStaticChain := func() error {
-		debugf("STATIC CHAIN LENGTH: %d", len(collections[staticGroup]))
+		tr.debugf("STATIC CHAIN LENGTH: %d", len(collections[staticGroup]))
 		for _, inj := range collections[staticGroup] {
-			debugf("STATIC CHAIN CALLING %s", inj)
+			tr.debugf("STATIC CHAIN CALLING %s", inj)
 
 			err := inj.wrapStaticInjector(baseValues)
 			if err != nil {
-				debugf("STATIC CHAIN RETURNING EARLY DUE TO ERROR %s", err)
+				tr.debugf("STATIC CHAIN RETURNING EARLY DUE TO ERROR %s", err)
 				return err
 			}
 		}
@@ -335,26 +357,26 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 			return err
 		}
 
-		inMap, err := generateInputMapper(initF, 0, bypassParams, initF.bypassRmap, downVmap, "init results")
+		inMap, err := generateInputMapper(initF, 0, bypassParams, initF.bypassRmap, downVmap, "init results", tr)
 		if err != nil {
 			return err
 		}
 
-		debugln("SET INIT FUNC")
+		tr.debugln("SET INIT FUNC")
 		if isReal {
 			initImp := func(inputs []reflect.Value) []reflect.Value {
-				debugln("INSIDE INIT")
+				tr.debugln("INSIDE INIT")
 				// if initDone panic, return error, or ignore?
 				initOnce.Do(func() {
 					outMap(baseValues, inputs)
-					debugln("RUN STATIC CHAIN")
+					tr.debugln("RUN STATIC CHAIN")
 					_ = runStaticChain()
 				})
-				dumpValueArray(baseValues, "base values before init return", downVmap)
+				tr.dumpValueArray(baseValues, "base values before init return", downVmap)
 				out := inMap(baseValues)
-				debugln("DONE INIT")
-				dumpValueArray(out, "init return", nil)
-				dumpF("init", initF)
+				tr.debugln("DONE INIT")
+				tr.dumpValueArray(out, "init return", nil)
+				tr.dumpF("init", initF)
 
 				return out
 			}
@@ -366,7 +388,7 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 						initImp))
 			}
 		}
-		debugln("SET INIT FUNC - DONE")
+		tr.debugln("SET INIT FUNC - DONE")
 	} else {
 		initFunc = func() {
 			initOnce.Do(func() {
@@ -382,19 +404,19 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 			return err
 		}
 
-		inMap, err := generateInputMapper(invokeF, 0, receivedParams, invokeF.upRmap, upVmap, "invoke results")
+		inMap, err := generateInputMapper(invokeF, 0, receivedParams, invokeF.upRmap, upVmap, "invoke results", tr)
 		if err != nil {
 			return err
 		}
 
-		debugln("SET INVOKE FUNC")
+		tr.debugln("SET INVOKE FUNC")
 		if isReal {
 			invokeImpl := func(inputs []reflect.Value) []reflect.Value {
 				initFunc()
 				values := baseValues.Copy()
-				dumpValueArray(values, "invoke - before input copy", downVmap)
+				tr.dumpValueArray(values, "invoke - before input copy", downVmap)
 				outMap(values, inputs)
-				dumpValueArray(values, "invoke - after input copy", downVmap)
+				tr.dumpValueArray(values, "invoke - after input copy", downVmap)
 				f(values)
 				return inMap(values)
 			}
@@ -406,7 +428,7 @@ func doBind(sc *Collection, originalInvokeF *provider, originalInitF *provider,
 						invokeImpl))
 			}
 		}
-		debugln("SET INVOKE FUNC - DONE")
+		tr.debugln("SET INVOKE FUNC - DONE")
 	}
 
 	return nil
@@ -444,6 +466,7 @@ func makeDebuggingProvider() (*provider, error) {
 		return nil, fmt.Errorf("internal error #29: problem with debugging injectors: %w", err)
 	}
 	d.isSynthetic = true
+	d.registeredAt, d.definedAt = "", ""
 	return d, nil
 }
 
@@ -461,6 +484,7 @@ func makeUnusedInputProvider() (*provider, error) {
 	}
 	d.isSynthetic = true
 	d.shun = true
+	d.registeredAt, d.definedAt = "", ""
 	return d, nil
 }
 
@@ -474,6 +498,7 @@ func makeUnusedReturnsProvider() (*provider, error) {
 	d.isSynthetic = true
 	d.shun = true
 	d.required = false
+	d.registeredAt, d.definedAt = "", ""
 	d.consumptionOptional = map[typeCode]struct{}{
 		unusedTypeCode: {},
 	}
@@ -483,72 +483,72 @@ func TestTerminalErrorMultiBinding(t *testing.T) {
 	wrapTest(t, func(t *testing.T) {
 		counts, c := terminalErrorSetup(t)
 
-		debugln("------------------------ bind with bind1Init ----------------")
+		t.Log("------------------------ bind with bind1Init ----------------")
 		var bind1Init func(s0) (s1, s1prime, error)
 		var bind1Invoke func(s3, s1prime, s2prime, s3prime) s7
 		err := c.Bind(&bind1Invoke, &bind1Init)
 		require.NoError(t, err)
 
-		debugln("------------------------ bind with bind2Init ----------------")
+		t.Log("------------------------ bind with bind2Init ----------------")
 		var bind2Init func(s0) (s1, s1prime, error)
 		var bind2Invoke func(s3, s1prime, s2prime, s3prime) s7prime
 		err = c.Bind(&bind2Invoke, &bind2Init)
 		require.NoError(t, err)
 
-		debugln("------------------------ call      bind1Init ----------------")
+		t.Log("------------------------ call      bind1Init ----------------")
 		bind1s1, bind1s1p, bind1e := bind1Init("s0 value")
 		require.Equal(t, s1Value, bind1s1)
 		require.Equal(t, s1prime("s1 prime"), bind1s1p)
 		require.NoError(t, bind1e)
 
-		debugln("------------------------ call bind1init (again) -------------")
+		t.Log("------------------------ call bind1init (again) -------------")
 		bind1s1, bind1s1p, bind1e = bind1Init("ignored")
 		require.Equal(t, s1Value, bind1s1)
 		require.Equal(t, s1prime("s1 prime"), bind1s1p)
 		require.NoError(t, bind1e)
 
-		debugln("------------------------ call      bind2Init ----------------")
+		t.Log("------------------------ call      bind2Init ----------------")
 		bind2s1, bind2s1p, bind2e := bind2Init("not s0 value")
 		require.Equal(t, s1Value, bind2s1)
 		require.Equal(t, s1prime("s1 prime"), bind2s1p)
 		require.Error(t, bind2e)
 
-		debugln("------------------------ call bind2init (again) -------------")
+		t.Log("------------------------ call bind2init (again) -------------")
 		bind2s1, bind2s1p, bind2e = bind2Init("s0 value")
 		require.Equal(t, s1Value, bind2s1)
 		require.Equal(t, s1prime("s1 prime"), bind2s1p)
 		require.Error(t, bind2e)
 
-		debugln("------------------------ call invoke1 -----------------------")
-		debugln("invoke1(s3=s3 value, s1p=s1 prime, s2p=s2 prime, s3p=s3 prime)")
+		t.Log("------------------------ call invoke1 -----------------------")
+		t.Log("invoke1(s3=s3 value, s1p=s1 prime, s2p=s2 prime, s3p=s3 prime)")
 		require.Equal(t, s7("s7 value"), bind1Invoke(s3Value, "s1 prime", "s2 prime", "s3 prime"))
 
-		debugln("------------------------ call invoke1 -----------------------")
-		debugln("invoke1(s3=s3 value, s1p=s1 other, s2p=s2 prime, s3p=s3 prime)")
+		t.Log("------------------------ call invoke1 -----------------------")
+		t.Log("invoke1(s3=s3 value, s1p=s1 other, s2p=s2 prime, s3p=s3 prime)")
 		require.Equal(t, s7("error"), bind1Invoke(s3Value, "s1 other", "s2 prime", "s3 prime"))
 
-		debugln("------------------------ call invoke1 -----------------------")
-		debugln("invoke1(s3=s3 value, s1p=s1 prime, s2p=s2 other, s3p=s3 prime)")
+		t.Log("------------------------ call invoke1 -----------------------")
+		t.Log("invoke1(s3=s3 value, s1p=s1 prime, s2p=s2 other, s3p=s3 prime)")
 		require.Equal(t, s7("error"), bind1Invoke(s3Value, "s1 prime", "s2 other", "s3 prime"))
 
-		debugln("------------------------ call invoke1 -----------------------")
-		debugln("invoke1(s3=s3 value, s1p=s1 prime, s2p=s2 prime, s3p=s3 other)")
+		t.Log("------------------------ call invoke1 -----------------------")
+		t.Log("invoke1(s3=s3 value, s1p=s1 prime, s2p=s2 prime, s3p=s3 other)")
 		require.Equal(t, s7("error"), bind1Invoke(s3Value, "s1 prime", "s2 prime", "s3 other"))
 
-		debugln("------------------------ call invoke2 -----------------------")
-		debugln("invoke2(s3=s3 value, s1p=s1 prime, s2p=s2 prime, s3p=s3 prime)")
+		t.Log("------------------------ call invoke2 -----------------------")
+		t.Log("invoke2(s3=s3 value, s1p=s1 prime, s2p=s2 prime, s3p=s3 prime)")
 		require.Equal(t, s7prime("s7 prime"), bind2Invoke(s3Value, "s1 prime", "s2 prime", "s3 prime"))
 
-		debugln("------------------------ call invoke2 -----------------------")
-		debugln("invoke2(s3=s3 value, s1p=s1 other, s2p=s2 prime, s3p=s3 prime)")
+		t.Log("------------------------ call invoke2 -----------------------")
+		t.Log("invoke2(s3=s3 value, s1p=s1 other, s2p=s2 prime, s3p=s3 prime)")
 		require.Equal(t, s7prime(""), bind2Invoke(s3Value, "s1 other", "s2 prime", "s3 prime"))
 
-		debugln("------------------------ call invoke2 -----------------------")
-		debugln("invoke2(s3=s3 value, s1p=s1 prime, s2p=s2 other, s3p=s3 prime)")
+		t.Log("------------------------ call invoke2 -----------------------")
+		t.Log("invoke2(s3=s3 value, s1p=s1 prime, s2p=s2 other, s3p=s3 prime)")
 		require.Equal(t, s7prime(""), bind2Invoke(s3Value, "s1 prime", "s2 other", "s3 prime"))
 
-		debugln("------------------------ call invoke2 -----------------------")
-		debugln("invoke2(s3=s3 value, s1p=s1 prime, s2p=s2 prime, s3p=s3 other)")
+		t.Log("------------------------ call invoke2 -----------------------")
+		t.Log("invoke2(s3=s3 value, s1p=s1 prime, s2p=s2 prime, s3p=s3 other)")
 		require.Equal(t, s7prime(""), bind2Invoke(s3Value, "s1 prime", "s2 prime", "s3 other"))
 
 		assert.Equal(t, 2, counts["s1"], "count for S1")
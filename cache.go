@@ -139,9 +139,9 @@ func canBeMapKey(in []reflect.Type) (bool, func([]reflect.Value) bool) {
 	}
 }
 
-func generateLookup(fm *provider, fv canCall, numInputs int) cacherFunc {
+func generateLookup(fm *provider, fv canCall, numInputs int, tr *tracer) cacherFunc {
 	if fm.memoized {
-		return generateCache(fm.id, fv, numInputs, fm.mapKeyCheck)
+		return generateCache(fm.id, fv, numInputs, fm.mapKeyCheck, tr)
 	}
 	if fm.singleton {
 		return generateSingleton(fm.id, fv)
@@ -168,14 +168,14 @@ func generateSingleton(id int32, fv canCall) cacherFunc {
 	return singleton
 }
 
-func generateCache(id int32, fv canCall, l int, okayCheck func([]reflect.Value) bool) cacherFunc {
+func generateCache(id int32, fv canCall, l int, okayCheck func([]reflect.Value) bool, tr *tracer) cacherFunc {
 	lockLock.Lock()
 	defer lockLock.Unlock()
 	if cacher, ok := cachers[id]; ok {
 		return cacher
 	}
 
-	cacher := defineCacher(id, fv, l, okayCheck)
+	cacher := defineCacher(id, fv, l, okayCheck, tr)
 	cachers[id] = cacher
 	return cacher
 }
@@ -197,7 +197,7 @@ func fillKeyFromInputs(key []any, in []reflect.Value) {
 	}
 }
 
-func defineCacher(_ int32, fv canCall, l int, okayCheck func([]reflect.Value) bool) cacherFunc {
+func defineCacher(_ int32, fv canCall, l int, okayCheck func([]reflect.Value) bool, tr *tracer) cacherFunc {
 	var lock sync.Mutex
 
 	switch {
@@ -274,7 +274,7 @@ func defineCacher(_ int32, fv canCall, l int, okayCheck func([]reflect.Value) bo
 		}
 
 	default:
-		debugf("number of arguments exceeds maximum!  %d", l)
+		tr.debugf("number of arguments exceeds maximum!  %d", l)
 		return func(in []reflect.Value) []reflect.Value {
 			return fv.Call(in)
 		}
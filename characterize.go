@@ -146,7 +146,6 @@ var (
 	markedMemoized       = predicate("is not marked Memoized", func(a testArgs) bool { return a.fm.memoize })
 	markedCacheable      = predicate("is not marked Cacheable", func(a testArgs) bool { return a.fm.cacheable })
 	markedSingleton      = predicate("is not marked Singleton", func(a testArgs) bool { return a.fm.singleton })
-	notMarkedReorder     = predicate("is marked Reorder", func(a testArgs) bool { return !a.fm.reorder })
 	notMarkedSingleton   = predicate("is marked Singleton", func(a testArgs) bool { return !a.fm.singleton })
 	notMarkedNoCache     = predicate("is marked NotCacheable", func(a testArgs) bool { return !a.fm.notCacheable })
 	mappableInputs       = predicate("has inputs that cannot be map keys", func(a testArgs) bool { return mappable(typesIn(a.t)...) })
@@ -278,7 +277,6 @@ var handlerRegistry = typeRegistry{
 			mappableInputs,
 			notMarkedNoCache,
 			mustNotMemoize,
-			notMarkedReorder,
 			isNotFuncPointer,
 		},
 		mutate: func(a testArgs) {
@@ -301,7 +299,6 @@ var handlerRegistry = typeRegistry{
 			mappableInputs,
 			notMarkedNoCache,
 			mustNotMemoize,
-			notMarkedReorder,
 			isNotFuncPointer,
 		},
 		mutate: func(a testArgs) {
@@ -326,7 +323,6 @@ var handlerRegistry = typeRegistry{
 			notMarkedNoCache,
 			possibleMapKey,
 			notMarkedSingleton,
-			notMarkedReorder,
 			isNotFuncPointer,
 		},
 		mutate: func(a testArgs) {
@@ -353,7 +349,6 @@ var handlerRegistry = typeRegistry{
 			notMarkedNoCache,
 			possibleMapKey,
 			notMarkedSingleton,
-			notMarkedReorder,
 			isNotFuncPointer,
 		},
 		mutate: func(a testArgs) {
@@ -378,7 +373,6 @@ var handlerRegistry = typeRegistry{
 			mustNotMemoize,
 			notMarkedNoCache,
 			notMarkedSingleton,
-			notMarkedReorder,
 			isNotFuncPointer,
 		},
 		mutate: func(a testArgs) {
@@ -44,7 +44,7 @@ func (c *Collection) Condense(treatErrorAsTerminal bool) (Provider, error) {
 	// upOut upflows.
 	{
 		nonStaticTypes := make(map[typeCode]bool)
-		beforeInvoke, afterInvoke, err := c.characterizeAndFlatten(nonStaticTypes)
+		beforeInvoke, afterInvoke, err := c.characterizeAndFlatten(nonStaticTypes, nil, newBindTracer(c))
 		if err != nil {
 			return nil, err
 		}
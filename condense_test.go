@@ -116,7 +116,7 @@ func TestCondenseSelfSatisfied(t *testing.T) {
 	assert.True(t, called, "condensed called")
 	assert.True(t, alsoCalled, "main called")
 	assert.Equal(t,
-		" [<reflectiveFunc>(int)]",
+		" [<reflectiveFunc>(int)] (registered condense_test.go:104)",
 		condensed.String(),
 		"presentation of condensed")
 }
@@ -125,10 +125,10 @@ func TestCondenseStringified(t *testing.T) {
 	c1 := nject.Sequence("x",
 		func() (int, string) { return 7, "foo3" },
 	).MustCondense(true)
-	assert.Equal(t, " [<reflectiveFunc>() (int, string)]", c1.String(), "c1")
+	assert.Equal(t, " [<reflectiveFunc>() (int, string)] (registered condense_test.go:127)", c1.String(), "c1")
 
 	c2 := nject.Sequence("x",
 		func() float32 { return 7 },
 	).MustCondense(true)
-	assert.Equal(t, " [<reflectiveFunc>() float32]", c2.String(), "c2")
+	assert.Equal(t, " [<reflectiveFunc>() float32] (registered condense_test.go:132)", c2.String(), "c2")
 }
@@ -0,0 +1,277 @@
+package nject
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// AutoDeref is a directive that allows a *T to be used where a T is
+// wanted.  After each provider of *T, a conversion from *T to T is
+// added to the injection chain.  Values of *T that are parameters of
+// the invoke or init functions are converted too.  A nil *T converts to the zero T.
+//
+// Like all conversions, the added conversions are marked Shun so they
+// are only used when nothing else provides T.  They are visible in
+// Debugging.  AutoDeref is not itself a provider.
+func AutoDeref[T any]() Provider {
+	ptr := reflect.TypeOf((*T)(nil))
+	t := ptr.Elem()
+	fn := reflect.MakeFunc(reflect.FuncOf([]reflect.Type{ptr}, []reflect.Type{t}, false),
+		func(args []reflect.Value) []reflect.Value {
+			if args[0].IsNil() {
+				return []reflect.Value{reflect.Zero(t)}
+			}
+			return []reflect.Value{args[0].Elem()}
+		}).Interface()
+	return newConversion("AutoDeref", ptr, t, fn)
+}
+
+// AutoAddr is a directive that allows a T to be used where a *T
+// is wanted.  After each provider of T, a conversion from T to *T is
+// added to the injection chain.  The *T points to a copy of the T.
+//
+// AutoAddr is not itself a provider.  See AutoDeref.
+func AutoAddr[T any]() Provider {
+	ptr := reflect.TypeOf((*T)(nil))
+	t := ptr.Elem()
+	fn := reflect.MakeFunc(reflect.FuncOf([]reflect.Type{t}, []reflect.Type{ptr}, false),
+		func(args []reflect.Value) []reflect.Value {
+			p := reflect.New(t)
+			p.Elem().Set(args[0])
+			return []reflect.Value{p}
+		}).Interface()
+	return newConversion("AutoAddr", t, ptr, fn)
+}
+
+// Convert is a directive that allows a From to be used where a To
+// is wanted.  After each provider of From, a conversion, using fn, is
+// added to the injection chain.  This is useful for named types:
+//
+//	Convert(func(s MyString) string { return string(s) })
+//
+// Convert is not itself a provider.  See AutoDeref.
+func Convert[From any, To any](fn func(From) To) Provider {
+	fm := newConversion("Convert",
+		reflect.TypeOf((*From)(nil)).Elem(),
+		reflect.TypeOf((*To)(nil)).Elem(),
+		fn)
+	if fn == nil {
+		fm.fatal = fmt.Errorf("Convert: conversion function is nil")
+	}
+	return fm
+}
+
+// conversion is a directive to add a provider that converts from one
+// type to another after each provider of the first type.
+type conversion struct {
+	from typeCode
+	to   typeCode
+}
+
+func newConversion(name string, from reflect.Type, to reflect.Type, fn any) *provider {
+	fm := newProvider(fn, -1, name)
+	fm.registeredAt = callerLocation()
+	fm.conversion = &conversion{
+		from: getTypeCode(from),
+		to:   getTypeCode(to),
+	}
+	return fm
+}
+
+// handleConversions removes the conversion directives and adds a
+// conversion after each provider of the types they convert from.  It
+// happens after handleDefaults and before characterization.  The
+// chainInputs are the types provided by the invoke and init functions:
+// conversions from those are added at the start of the chain.
+func (c *Collection) handleConversions(chainInputs []typeCode, tr *tracer) error {
+	var conversions []*provider
+	for _, fm := range c.contents {
+		if fm.conversion != nil {
+			if fm.fatal != nil {
+				return fm.fatal
+			}
+			conversions = append(conversions, fm)
+		}
+	}
+	if len(conversions) == 0 {
+		return nil
+	}
+	contents := make([]*provider, 0, len(c.contents)+len(conversions))
+	addAdapters := func(outputs map[typeCode]struct{}, after string) {
+		for _, conv := range conversions {
+			if _, ok := outputs[conv.conversion.from]; !ok {
+				continue
+			}
+			if _, ok := outputs[conv.conversion.to]; ok {
+				continue
+			}
+			adapter := conv.copy()
+			adapter.id = atomic.AddInt32(&idCounter, 1)
+			adapter.conversion = nil
+			adapter.shun = true
+			adapter.nonFinal = true
+			tr.decision(adapter, "convert", fmt.Sprintf("%s to %s after %s", conv.conversion.from, conv.conversion.to, after))
+			contents = append(contents, adapter)
+		}
+	}
+	if len(chainInputs) > 0 {
+		inputs := make(map[typeCode]struct{}, len(chainInputs))
+		for _, tc := range chainInputs {
+			inputs[tc] = struct{}{}
+		}
+		addAdapters(inputs, "the invoke and init functions")
+	}
+	for _, fm := range c.contents {
+		if fm.conversion != nil {
+			continue
+		}
+		contents = append(contents, fm)
+		if fm.fatal != nil {
+			continue
+		}
+		addAdapters(providerOutputs(fm), fm.String())
+	}
+	c.contents = contents
+	return nil
+}
+
+// As annotates a provider to say that, in addition to the type that it
+// provides, it also provides T.  One of the values that the provider
+// passes down the chain must be assignable to T.  For a wrapper, those
+// are the parameters to inner().
+//
+//	nject.As[io.Reader](nject.As[io.Closer](OpenFile)) // provides *os.File, io.Reader, and io.Closer
+//
+// Unlike Loose, the value is provided under T as a real output: it shows
+// up in DownFlows and UpFlows, and is considered by Reorder and the
+// shadowing checks.
+//
+// As can be applied to a Collection.  It then applies to the members of
+// the collection that provide a value assignable to T.
+//
+// When used on an existing Provider, it creates an annotated copy of that provider.
+//
+// As may be called on the provider it returns creating a provider
+// that provides multiple additional types.
+func As[T any](fn any) Provider {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	th := newThing(fn)
+	var isCollection bool
+	switch th.(type) {
+	case *Collection, Collection:
+		isCollection = true
+	}
+	return th.modify(func(fm *provider) {
+		if fm.fatal != nil {
+			return
+		}
+		nfn, err := publishAs(fm.baseFn(), t)
+		switch {
+		case errors.Is(err, errNotAssignable) && isCollection:
+			// only members that provide something assignable are changed
+		case err != nil:
+			fm.fatal = fmt.Errorf("As[%s]: %w", t, err)
+		default:
+			fm.replaceFn(nfn)
+		}
+	})
+}
+
+var errNotAssignable = errors.New("no value provided is assignable")
+
+// publishAs creates a new function that is the same as fn but also
+// provides t.
+func publishAs(fn any, t reflect.Type) (any, error) {
+	switch fn.(type) {
+	case Reflective, generatedFromInjectionChain:
+		return nil, fmt.Errorf("cannot be applied to %T", fn)
+	}
+	v := reflect.ValueOf(fn)
+	if !v.IsValid() {
+		return nil, errNotAssignable
+	}
+	ft := v.Type()
+	if ft.Kind() != reflect.Func {
+		i, err := assignableIndex([]reflect.Type{ft}, t)
+		if err != nil || i == -1 {
+			return fn, err
+		}
+		return reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{ft, t}, false),
+			func([]reflect.Value) []reflect.Value {
+				return []reflect.Value{v, assignTo(v, t)}
+			}).Interface(), nil
+	}
+	if ft.IsVariadic() {
+		return nil, fmt.Errorf("cannot be applied to variadic function %s", ft)
+	}
+	inputs := typesIn(ft)
+	if len(inputs) > 0 && inputs[0].Kind() == reflect.Func {
+		innerT := inputs[0]
+		if innerT.IsVariadic() {
+			return nil, fmt.Errorf("cannot be applied to wrapper with variadic inner function %s", innerT)
+		}
+		innerInputs := typesIn(innerT)
+		i, err := assignableIndex(innerInputs, t)
+		if err != nil || i == -1 {
+			return fn, err
+		}
+		newInnerT := reflect.FuncOf(append(innerInputs, t), typesOut(innerT), false)
+		inputs[0] = newInnerT
+		return reflect.MakeFunc(reflect.FuncOf(inputs, typesOut(ft), false),
+			func(args []reflect.Value) []reflect.Value {
+				newInner := args[0]
+				args[0] = reflect.MakeFunc(innerT, func(innerArgs []reflect.Value) []reflect.Value {
+					return newInner.Call(append(innerArgs, assignTo(innerArgs[i], t)))
+				})
+				return v.Call(args)
+			}).Interface(), nil
+	}
+	outputs := typesOut(ft)
+	i, err := assignableIndex(outputs, t)
+	if err != nil || i == -1 {
+		return fn, err
+	}
+	newOutputs := make([]reflect.Type, 0, len(outputs)+1)
+	newOutputs = append(newOutputs, outputs[:i+1]...)
+	newOutputs = append(newOutputs, t)
+	newOutputs = append(newOutputs, outputs[i+1:]...)
+	return reflect.MakeFunc(reflect.FuncOf(inputs, newOutputs, false),
+		func(args []reflect.Value) []reflect.Value {
+			out := v.Call(args)
+			newOut := make([]reflect.Value, 0, len(out)+1)
+			newOut = append(newOut, out[:i+1]...)
+			newOut = append(newOut, assignTo(out[i], t))
+			return append(newOut, out[i+1:]...)
+		}).Interface(), nil
+}
+
+// assignableIndex returns the index of the one type in list that
+// is assignable to t.  It returns -1 if t is already in the list.
+func assignableIndex(list []reflect.Type, t reflect.Type) (int, error) {
+	found := -1
+	for i, lt := range list {
+		switch {
+		case lt == t:
+			return -1, nil
+		case lt == terminalErrorType:
+			continue
+		case !lt.AssignableTo(t):
+			continue
+		case found != -1:
+			return -1, fmt.Errorf("more than one value provided is assignable: %s, %s", list[found], lt)
+		}
+		found = i
+	}
+	if found == -1 {
+		return -1, errNotAssignable
+	}
+	return found, nil
+}
+
+func assignTo(v reflect.Value, t reflect.Type) reflect.Value {
+	n := reflect.New(t).Elem()
+	n.Set(v)
+	return n
+}
@@ -0,0 +1,242 @@
+package nject
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoDeref(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got s1
+		require.NoError(t, Run(t.Name(),
+			AutoDeref[s1](),
+			func() *s1 { s := s1("pointed"); return &s },
+			func(s s1) { got = s },
+		))
+		assert.Equal(t, s1("pointed"), got)
+
+		require.NoError(t, Run(t.Name(),
+			AutoDeref[s1](),
+			func() *s1 { return nil },
+			func(s s1) { got = s },
+		))
+		assert.Equal(t, s1(""), got, "nil converts to zero")
+	})
+}
+
+func TestAutoAddr(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got *s1
+		require.NoError(t, Run(t.Name(),
+			s1("value"),
+			AutoAddr[s1](),
+			func(s *s1) { got = s },
+		))
+		require.NotNil(t, got)
+		assert.Equal(t, s1("value"), *got)
+	})
+}
+
+func TestConvert(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got string
+		require.NoError(t, Run(t.Name(),
+			Convert(func(s s1) string { return "converted " + string(s) }),
+			s1("named"),
+			func(s string) { got = s },
+		))
+		assert.Equal(t, "converted named", got)
+	})
+}
+
+func TestConvertExplicitWins(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got string
+		var converted bool
+		require.NoError(t, Run(t.Name(),
+			Convert(func(s s1) string { converted = true; return string(s) }),
+			s1("named"),
+			"explicit",
+			func(s string) { got = s },
+		))
+		assert.Equal(t, "explicit", got)
+		assert.False(t, converted)
+	})
+}
+
+func TestConvertNotUsedWithoutSource(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		err := Run(t.Name(),
+			Convert(func(s s1) string { return string(s) }),
+			func(_ string) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no match for its input parameter string")
+
+		err = Run(t.Name(),
+			Convert[s1, string](nil),
+			s1("x"),
+			func(_ string) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "conversion function is nil")
+	})
+}
+
+func TestConvertInvokeAndInit(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var gotS1 s1
+		var gotString string
+		var invoke func(*s1)
+		var initF func(s2)
+		require.NoError(t, Sequence(t.Name(),
+			AutoDeref[s1](),
+			Convert(func(s s2) string { return "converted " + string(s) }),
+			func(a s1, b string) {
+				gotS1 = a
+				gotString = b
+			},
+		).Bind(&invoke, &initF))
+		initF("init")
+		v := s1("invoke")
+		invoke(&v)
+		assert.Equal(t, s1("invoke"), gotS1)
+		assert.Equal(t, "converted init", gotString)
+	})
+}
+
+func TestConvertInDebugging(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var called bool
+		require.NoError(t, Run(t.Name(),
+			AutoDeref[s1](),
+			func() *s1 { s := s1("x"); return &s },
+			func(_ s1, d *Debugging) {
+				called = true
+				var found bool
+				for _, s := range d.Included {
+					if strings.Contains(s, "AutoDeref [func(*nject.s1) nject.s1]") {
+						found = true
+					}
+				}
+				assert.True(t, found, "conversion included:\n%s", strings.Join(d.Included, "\n"))
+				assert.Contains(t, d.Trace, "convert")
+			},
+		))
+		assert.True(t, called)
+	})
+}
+
+type asImpl string
+
+func (a asImpl) String() string { return string(a) }
+func (a asImpl) Error() string  { return "error: " + string(a) }
+
+func TestAs(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var gotStringer fmt.Stringer
+		var gotError error
+		var gotImpl asImpl
+		require.NoError(t, Run(t.Name(),
+			As[fmt.Stringer](As[error](func() (asImpl, TerminalError) { return "x", nil })),
+			func(s fmt.Stringer, e error, a asImpl) {
+				gotStringer, gotError, gotImpl = s, e, a
+			},
+		))
+		assert.Equal(t, "x", gotStringer.String())
+		assert.Equal(t, "error: x", gotError.Error())
+		assert.Equal(t, asImpl("x"), gotImpl)
+	})
+}
+
+func TestAsWrapperAndLiteral(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got fmt.Stringer
+		require.NoError(t, Run(t.Name(),
+			As[fmt.Stringer](func(inner func(asImpl)) { inner("wrapped") }),
+			func(s fmt.Stringer) { got = s },
+		))
+		assert.Equal(t, "wrapped", got.String())
+
+		require.NoError(t, Run(t.Name(),
+			As[fmt.Stringer](asImpl("literal")),
+			func(s fmt.Stringer) { got = s },
+		))
+		assert.Equal(t, "literal", got.String())
+	})
+}
+
+func TestAsFlows(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		p := As[fmt.Stringer](func(_ s0) asImpl { return "" })
+		inputs, outputs := p.DownFlows()
+		assert.Equal(t, "[nject.s0]", fmt.Sprint(inputs))
+		assert.Equal(t, "[nject.asImpl fmt.Stringer]", fmt.Sprint(outputs))
+
+		var called bool
+		require.NoError(t, Run(t.Name(),
+			Reorder(func(s fmt.Stringer) s1 { return s1(s.String()) }),
+			As[fmt.Stringer](asImpl("reordered")),
+			func(s s1) {
+				called = true
+				assert.Equal(t, s1("reordered"), s)
+			},
+		))
+		assert.True(t, called)
+	})
+}
+
+func TestAsCollection(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got fmt.Stringer
+		require.NoError(t, Run(t.Name(),
+			As[fmt.Stringer](Sequence("seq",
+				s0("not a stringer"),
+				asImpl("collection"),
+			)),
+			func(_ s0, s fmt.Stringer) { got = s },
+		))
+		assert.Equal(t, "collection", got.String())
+	})
+}
+
+func TestAsErrors(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		err := Run(t.Name(),
+			As[fmt.Stringer](func() s0 { return "" }),
+			func(_ fmt.Stringer) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "As[fmt.Stringer]: no value provided is assignable")
+
+		err = Run(t.Name(),
+			As[fmt.Stringer](func() (asImpl, *asImpl) { return "", nil }),
+			func(_ fmt.Stringer) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "more than one value provided is assignable: nject.asImpl, *nject.asImpl")
+	})
+}
+
+func TestAsMemoized(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		p := Memoize(func() asImpl { return "memoized" })
+		var got asImpl
+		require.NoError(t, Run(t.Name(),
+			p,
+			func(a asImpl) { got = a },
+		))
+		assert.Equal(t, asImpl("memoized"), got)
+
+		var gotStringer fmt.Stringer
+		require.NoError(t, Run(t.Name(),
+			As[fmt.Stringer](p),
+			func(s fmt.Stringer) { gotStringer = s },
+		))
+		assert.Equal(t, "memoized", gotStringer.String())
+	})
+}
@@ -8,79 +8,210 @@ import (
 	"sync/atomic"
 )
 
-var (
-	debugLock     sync.RWMutex
-	debug         uint32
-	debugOutput   string
-	debugOutputMu sync.Mutex
-)
+// tracer collects the internal debugging trace for a single Bind().
+// Each Bind() that is traced has its own tracer so that concurrent
+// Bind()s never contend with each other.  A nil *tracer is valid
+// and discards everything: that's what is used for normal binds.
+//
+// A tracer is cheap to copy: tr.inPhase() returns a tracer that shares
+// the same output but labels what it records with a different phase.
+type tracer struct {
+	sink  *traceSink
+	phase tracePhase
+}
+
+// traceSink is where the trace for one Bind() goes
+type traceSink struct {
+	mu      sync.Mutex
+	output  strings.Builder
+	hooks   *traceHooks
+	target  *traceTarget // structured output, see slog.go
+	capture bool         // collecting the trace for Debugging
+	bound   int32        // set when binding is done, accessed atomically
+}
+
+// traceTarget receives structured trace output while binding
+type traceTarget struct {
+	enabled func() bool
+	event   func(traceEvent)
+}
 
-var (
-	debuglnHook func(...any)
-	debugfHook  func(string, ...any)
+// traceHooks redirect the trace output
+type traceHooks struct {
+	ln func(...any)
+	f  func(string, ...any)
+}
+
+type tracePhase string
+
+const (
+	phaseBind         tracePhase = "bind"
+	phaseReplace      tracePhase = "replace"
+	phaseCharacterize tracePhase = "characterize"
+	phaseInclude      tracePhase = "include"
+	phaseReorder      tracePhase = "reorder"
+	phaseGenerate     tracePhase = "generate"
 )
 
-func debugEnabled() bool {
-	return atomic.LoadUint32(&debug) == 1
+// traceEvent is a structured version of one line of the trace. The provider
+// and types are picked out of the arguments to debugln and debugf.
+type traceEvent struct {
+	phase    tracePhase
+	message  string
+	provider *provider
+	types    []reflect.Type
+	decision string
 }
 
-func debugln(stuff ...any) {
-	if !debugEnabled() {
-		return
+// globalTraceHooks, when set, causes every Bind() to be traced
+// using the hooks.  This is used by tests.
+var globalTraceHooks atomic.Value // *traceHooks
+
+// newBindTracer returns the tracer to use for a regular Bind().  That
+// is nil unless globalTraceHooks are set or the collection includes
+// a TraceTo directive.
+func newBindTracer(c *Collection) *tracer {
+	sink := &traceSink{}
+	if hooks, ok := globalTraceHooks.Load().(*traceHooks); ok && hooks != nil {
+		sink.hooks = hooks
+	}
+	for _, fm := range c.contents {
+		if fm.traceTo != nil {
+			sink.target = fm.traceTo
+		}
+	}
+	if sink.hooks == nil && sink.target == nil {
+		return nil
+	}
+	return &tracer{sink: sink, phase: phaseBind}
+}
+
+func newCaptureTracer() *tracer {
+	return &tracer{sink: &traceSink{capture: true}, phase: phaseBind}
+}
+
+// capturing is true when the trace is being collected for Debugging
+// rather than sent to TraceTo or the global hooks
+func (tr *tracer) capturing() bool {
+	return tr != nil && tr.sink.capture
+}
+
+// enabled is false when nothing would be done with the trace.  Once
+// binding is done, the trace is not sent to a TraceTo target since
+// that would log the injected values each time the chain is invoked.
+func (tr *tracer) enabled() bool {
+	if tr == nil {
+		return false
+	}
+	switch {
+	case tr.sink.hooks != nil:
+		return true
+	case tr.sink.target != nil:
+		return atomic.LoadInt32(&tr.sink.bound) == 0 &&
+			(tr.sink.target.enabled == nil || tr.sink.target.enabled())
+	default:
+		return true
+	}
+}
+
+// doneBinding marks the end of the bind-time trace
+func (tr *tracer) doneBinding() {
+	if tr != nil {
+		atomic.StoreInt32(&tr.sink.bound, 1)
 	}
+}
 
-	debugOutputMu.Lock()
-	if debuglnHook != nil {
-		debuglnHook(stuff...)
-	} else {
-		debugOutput += fmt.Sprintln(stuff...)
+// inPhase returns a tracer that shares output with tr
+func (tr *tracer) inPhase(phase tracePhase) *tracer {
+	if tr == nil {
+		return nil
 	}
-	debugOutputMu.Unlock()
+	return &tracer{sink: tr.sink, phase: phase}
 }
 
-func debugf(format string, stuff ...any) {
-	if !debugEnabled() {
+func (tr *tracer) debugln(stuff ...any) {
+	if !tr.enabled() {
 		return
 	}
+	tr.record(traceEvent{message: strings.TrimSuffix(fmt.Sprintln(stuff...), "\n")}, stuff, func() {
+		tr.sink.hooks.ln(stuff...)
+	})
+}
 
-	debugOutputMu.Lock()
-	if debugfHook != nil {
-		debugfHook(format, stuff...)
-	} else {
-		debugOutput += fmt.Sprintf(format+"\n", stuff...)
+func (tr *tracer) debugf(format string, stuff ...any) {
+	if !tr.enabled() {
+		return
 	}
-	debugOutputMu.Unlock()
+	tr.record(traceEvent{message: fmt.Sprintf(format, stuff...)}, stuff, func() {
+		tr.sink.hooks.f(format, stuff...)
+	})
 }
 
-func captureDoBindDebugging(sc *Collection, invokeF *provider, initF *provider) string {
-	debugLock.Lock()
-	if atomic.SwapUint32(&debug, 1) == 1 {
-		return "already capturing"
+// decision records what was decided about a provider and why
+func (tr *tracer) decision(fm *provider, decision string, why any) {
+	if !tr.enabled() {
+		return
+	}
+	stuff := []any{fm, why}
+	tr.record(traceEvent{message: fmt.Sprintf("\t%s %s --- %s", decision, fm, why), decision: decision}, stuff, func() {
+		tr.sink.hooks.f("\t%s %s --- %s", decision, fm, why)
+	})
+}
+
+func (tr *tracer) record(event traceEvent, stuff []any, hook func()) {
+	tr.sink.mu.Lock()
+	defer tr.sink.mu.Unlock()
+	switch {
+	case tr.sink.hooks != nil:
+		hook()
+	case tr.sink.target == nil:
+		tr.sink.output.WriteString(event.message)
+		tr.sink.output.WriteString("\n")
+	}
+	if tr.sink.target != nil && atomic.LoadInt32(&tr.sink.bound) == 0 {
+		event.phase = tr.phase
+		for _, thing := range stuff {
+			switch v := thing.(type) {
+			case *provider:
+				if event.provider == nil {
+					event.provider = v
+				}
+			case typeCode:
+				event.types = append(event.types, v.Type())
+			case reflect.Type:
+				event.types = append(event.types, v)
+			}
+		}
+		tr.sink.target.event(event)
 	}
-	defer func() {
-		atomic.StoreUint32(&debug, 0)
-		debugLock.Unlock()
-	}()
+}
 
-	debugOutputMu.Lock()
-	debugOutput = ""
-	debugOutputMu.Unlock()
+// String returns the trace collected so far
+func (tr *tracer) String() string {
+	if tr == nil {
+		return ""
+	}
+	tr.sink.mu.Lock()
+	defer tr.sink.mu.Unlock()
+	return tr.sink.output.String()
+}
 
-	_ = doBind(sc, invokeF, initF, false)
+// captureDoBindDebugging re-runs the binding process with a fresh tracer
+// so that the trace is specific to this collection.
+func captureDoBindDebugging(sc *Collection, invokeF *provider, initF *provider) string {
+	tr := newCaptureTracer()
+	_ = doBind(sc, invokeF, initF, false, tr, nil)
 
 	funcs := make([]*provider, len(sc.contents))
 	for i, f := range sc.contents {
 		funcs[i], _ = characterizeFunc(f, charContext{inputsAreStatic: true})
 	}
 	reproduce := generateReproduce(funcs, invokeF, initF)
-	debugOutputMu.Lock()
-	debugStr := debugOutput + "\n\n\n" + reproduce
-	debugOutputMu.Unlock()
-	return debugStr
+	return tr.String() + "\n\n\n" + reproduce
 }
 
-func dumpValueArray(va []reflect.Value, context string, vMap map[typeCode]int) {
-	if !debugEnabled() {
+func (tr *tracer) dumpValueArray(va []reflect.Value, context string, vMap map[typeCode]int) {
+	if !tr.enabled() {
 		return
 	}
 	if len(vMap) > 0 {
@@ -91,18 +222,18 @@ func dumpValueArray(va []reflect.Value, context string, vMap map[typeCode]int) {
 
 		for i, v := range va {
 			if v.IsValid() {
-				debugf("value at %s: %d: %s: %s: %v", context, i, reverseMap[i], v.Type(), v.Interface())
+				tr.debugf("value at %s: %d: %s: %s: %v", context, i, reverseMap[i], v.Type(), v.Interface())
 			} else {
-				debugf("value at %s: %d: %s: UNINITIALIZED", context, i, reverseMap[i])
+				tr.debugf("value at %s: %d: %s: UNINITIALIZED", context, i, reverseMap[i])
 			}
 		}
 		return
 	}
 	for i, v := range va {
 		if v.IsValid() {
-			debugf("value at %s: %d: %s: %v", context, i, v.Type(), v.Interface())
+			tr.debugf("value at %s: %d: %s: %v", context, i, v.Type(), v.Interface())
 		} else {
-			debugf("value at %s: %d: UNINITIALIZED", context, i)
+			tr.debugf("value at %s: %d: UNINITIALIZED", context, i)
 		}
 	}
 }
@@ -116,12 +247,12 @@ func dumpVmap(context string, vMap map[typeCode]int) {
 	for tc, i := range vMap {
 		out += fmt.Sprintf("\n\t%s -> %d", tc.Type(), i)
 	}
-	debugln(out)
+	tr.debugln(out)
 
 */
 
-func dumpF(context string, fm *provider) {
-	if !debugEnabled() {
+func (tr *tracer) dumpF(context string, fm *provider) {
+	if !tr.enabled() {
 		return
 	}
 	var out string
@@ -129,7 +260,7 @@ func dumpF(context string, fm *provider) {
 	out += fmt.Sprintf("\n\tclass: %s\n\tgroup: %s", fm.class, fm.group)
 	for name, flow := range fm.flows {
 		if len(flow) > 0 {
-			out += fmt.Sprintf("\n\t%s flow: %s", flowType(name), formatFlow(flow))
+			out += fmt.Sprintf("\n\t%s flow: %s", flowType(name), tr.formatFlow(flow))
 		}
 	}
 	for upDown, rMap := range map[string]map[typeCode]typeCode{
@@ -171,11 +302,11 @@ func dumpF(context string, fm *provider) {
 	for _, dep := range fm.d.usedBy {
 		out += fmt.Sprintf("\n\tUSED BY: %s", dep)
 	}
-	debugln(out)
+	tr.debugln(out)
 }
 
-func formatFlow(flow []typeCode) string {
-	if !debugEnabled() {
+func (tr *tracer) formatFlow(flow []typeCode) string {
+	if !tr.enabled() {
 		return ""
 	}
 	var types []string
@@ -249,6 +380,9 @@ func generateReproduce(funcs []*provider, invokeF *provider, initF *provider) st
 			"Reorder":      fm.reorder,
 			"Desired":      fm.desired,
 			"Shun":         fm.shun,
+			"Strict":       fm.strict,
+			"Default":      fm.isDefault,
+			"Override":     fm.isOverride,
 			"NotCacheable": fm.notCacheable,
 			"Singleton":    fm.singleton,
 		} {
@@ -262,6 +396,7 @@ func generateReproduce(funcs []*provider, invokeF *provider, initF *provider) st
 			"Loose":               fm.loose,
 			"MustConsume":         fm.mustConsume,
 			"ConsumptionOptional": fm.consumptionOptional,
+			"Exclusive":           fm.exclusive,
 		} {
 			for tc := range m {
 				f += anno + "[" + tc.String() + "]("
@@ -310,15 +445,11 @@ func generateReproduce(funcs []*provider, invokeF *provider, initF *provider) st
 					f += fmt.Sprintf(" { called[%q]++ }", n)
 				}
 			}
-			f += closeParens + ","
-			if fm.include {
-				f += " // included"
-			}
-			f += "\n"
+			f += closeParens + "," + reproduceComment(fm) + "\n"
 		} else {
 			tca := substituteTypes(subs, &t, []reflect.Type{typ})
 			def := substituteDefaults(subs, []reflect.Type{typ})
-			f += fmt.Sprintf("%s(%s)%s,\n", tca[0], def[0], closeParens)
+			f += fmt.Sprintf("%s(%s)%s,%s\n", tca[0], def[0], closeParens, reproduceComment(fm))
 		}
 	}
 	if inCluster != 0 {
@@ -334,6 +465,21 @@ func generateReproduce(funcs []*provider, invokeF *provider, initF *provider) st
 	return "func TestRegression(t *testing.T) {\n" + t + "\n" + f
 }
 
+// reproduceComment notes if the provider was included and where it came from
+func reproduceComment(fm *provider) string {
+	var notes []string
+	if fm.include {
+		notes = append(notes, "included")
+	}
+	if loc := fm.location(); loc != "" {
+		notes = append(notes, loc)
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return " // " + strings.Join(notes, "; ")
+}
+
 // TODO: take note of which interfaces implement each other and new interfaces that
 // follow the same pattern.
 func substituteTypes(subs map[typeCode]string, defineTypes *string, types []reflect.Type) []string {
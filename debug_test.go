@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-	"sync/atomic"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,23 +12,18 @@ import (
 )
 
 func debugOn(t *testing.T) {
-	debugOutputMu.Lock()
-	debuglnHook = func(stuff ...any) {
-		t.Log(stuff...)
-	}
-	debugfHook = func(format string, stuff ...any) {
-		t.Logf(format+"\n", stuff...)
-	}
-	debugOutputMu.Unlock()
-	atomic.StoreUint32(&debug, 1)
+	globalTraceHooks.Store(&traceHooks{
+		ln: func(stuff ...any) {
+			t.Log(stuff...)
+		},
+		f: func(format string, stuff ...any) {
+			t.Logf(format+"\n", stuff...)
+		},
+	})
 }
 
 func debugOff() {
-	debugOutputMu.Lock()
-	debuglnHook = nil
-	debugfHook = nil
-	debugOutputMu.Unlock()
-	atomic.StoreUint32(&debug, 0)
+	globalTraceHooks.Store((*traceHooks)(nil))
 }
 
 func wrapTest(t *testing.T, inner func(*testing.T)) {
@@ -106,3 +101,31 @@ func TestNilDetailedError(t *testing.T) {
 	var err error
 	require.NoError(t, err, DetailedError(err))
 }
+
+func TestParallelDebugging(t *testing.T) {
+	t.Parallel()
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	traces := make([]string, n)
+	for i := 0; i < n; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = Run(fmt.Sprintf("parallel-%d", i),
+				func() int { return i },
+				func(_ int, d *Debugging) {
+					traces[i] = d.Trace
+				},
+			)
+			_ = DetailedError(Run("parallel-fail", func(_ s2) {}))
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < n; i++ {
+		require.NoError(t, errs[i])
+		require.Contains(t, traces[i], "BEGIN characterizeAndFlatten", "trace %d", i)
+		require.Equal(t, 1, strings.Count(traces[i], "BEGIN characterizeAndFlatten"), "trace %d is only for its own bind", i)
+	}
+}
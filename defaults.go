@@ -0,0 +1,224 @@
+package nject
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Default annotates a provider as providing default values.  A
+// Default is only used when there is no Override and no other
+// provider of its outputs is included anywhere in the chain.  Unlike
+// using Shun, it does not matter where the other provider is
+// positioned in the chain.  Providers that consume the same type that
+// they provide (modifiers) do not count as other providers.
+//
+// Default can be applied to a Collection.  It then applies to
+// each member of the collection separately.
+//
+// When used on an existing Provider, it creates an annotated copy of that provider.
+func Default(fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		fm.isDefault = true
+	})
+}
+
+// Override annotates a provider as replacing a Default.  The Override
+// is moved into the position of the Default (or the first Default if it
+// replaces more than one) so its position in the chain does not matter.
+//
+// It is an error if there is no Default that provides any of the
+// same types as the Override.  It is also an error for two Overrides to
+// provide the same type.
+//
+// Override can be applied to a Collection.  It then applies to
+// each member of the collection separately.
+//
+// When used on an existing Provider, it creates an annotated copy of that provider.
+func Override(fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		fm.isOverride = true
+	})
+}
+
+// handleDefaults removes Default providers that are not needed and
+// moves Override providers to the position of the Default they replace.
+// It happens after the handleReplaceByName and before characterization.
+func (c *Collection) handleDefaults(tr *tracer) error {
+	var hasDefaults bool
+	for _, fm := range c.contents {
+		if fm.isDefault || fm.isOverride {
+			hasDefaults = true
+			break
+		}
+	}
+	if !hasDefaults {
+		return nil
+	}
+
+	outputs := make([]map[typeCode]struct{}, len(c.contents))
+	inputs := make([]map[typeCode]struct{}, len(c.contents))
+	for i, fm := range c.contents {
+		if fm.fatal != nil {
+			return fm.fatal
+		}
+		inputs[i], outputs[i] = providerFlows(fm)
+	}
+	overlapTypes := func(i, j int) []typeCode {
+		var types []typeCode
+		for tc := range outputs[i] {
+			if _, ok := outputs[j][tc]; ok {
+				types = append(types, tc)
+			}
+		}
+		return types
+	}
+	overlaps := func(i, j int) []string {
+		types := make([]string, 0, len(outputs[i]))
+		for _, tc := range overlapTypes(i, j) {
+			types = append(types, tc.String())
+		}
+		sort.Strings(types)
+		return types
+	}
+
+	dropped := make(map[int]bool)
+	moveTo := make(map[int]int)      // override index -> default index
+	placeHere := make(map[int][]int) // default index -> override indexes
+	for i, fm := range c.contents {
+		if !fm.isOverride {
+			continue
+		}
+		first := -1
+		for j, d := range c.contents {
+			switch {
+			case j == i:
+				continue
+			case d.isOverride:
+				if types := overlaps(i, j); len(types) > 0 && j > i {
+					return fmt.Errorf("%s and %s are both Overrides of %s", fm, d, strings.Join(types, ", "))
+				}
+			case d.isDefault:
+				if types := overlaps(i, j); len(types) > 0 {
+					if err := checkSealed(overrideAction(types), fm, d); err != nil {
+						return err
+					}
+					dropped[j] = true
+					tr.decision(d, "default dropped", fmt.Sprintf("overridden by %s", fm))
+					if first == -1 {
+						first = j
+					}
+				}
+			}
+		}
+		if first == -1 {
+			var types []string
+			for tc := range outputs[i] {
+				types = append(types, tc.String())
+			}
+			sort.Strings(types)
+			return fmt.Errorf("%s is an Override but there is no Default for %s", fm, strings.Join(types, ", "))
+		}
+		moveTo[i] = first
+		placeHere[first] = append(placeHere[first], i)
+	}
+	// A Default that has other real producers of its types is kept, but
+	// marked Shun and, if the first of them is earlier, moved before it.
+	// It is then only included if the other producers are not.  If it
+	// is included anyway, computeDependenciesAndInclusion excludes it.
+	yields := make(map[int]int) // default index -> first producer index
+	for j, d := range c.contents {
+		if !d.isDefault || dropped[j] {
+			continue
+		}
+		for i, fm := range c.contents {
+			if fm.isDefault || fm.isOverride {
+				continue
+			}
+			var types []string
+			for _, tc := range overlapTypes(j, i) {
+				if _, modifier := inputs[i][tc]; !modifier {
+					types = append(types, tc.String())
+				}
+			}
+			if len(types) > 0 {
+				yields[j] = i
+				sort.Strings(types)
+				tr.decision(d, "default yields", fmt.Sprintf("%s provided by %s", strings.Join(types, ", "), fm))
+				break
+			}
+		}
+	}
+	placeBefore := make(map[int][]int) // producer index -> default indexes
+	for j, i := range yields {
+		if i < j {
+			placeBefore[i] = append(placeBefore[i], j)
+		}
+	}
+	for _, defaults := range placeBefore {
+		sort.Ints(defaults)
+	}
+	shunned := func(j int) *provider {
+		d := c.contents[j].copy()
+		d.shun = true
+		return d
+	}
+
+	contents := make([]*provider, 0, len(c.contents))
+	for i, fm := range c.contents {
+		for _, j := range placeBefore[i] {
+			contents = append(contents, shunned(j))
+		}
+		for _, o := range placeHere[i] {
+			tr.decision(c.contents[o], "override", fmt.Sprintf("moved to replace %s", fm))
+			contents = append(contents, c.contents[o])
+		}
+		if _, moved := moveTo[i]; moved || dropped[i] {
+			continue
+		}
+		if p, ok := yields[i]; ok {
+			if p > i {
+				contents = append(contents, shunned(i))
+			}
+			continue
+		}
+		contents = append(contents, fm)
+	}
+	c.contents = contents
+	return nil
+}
+
+// overrideAction describes an Override for checkSealed
+type overrideAction []string
+
+func (o overrideAction) String() string {
+	return "override " + strings.Join(o, ", ")
+}
+
+// providerFlows characterizes a provider, before the real
+// characterization, to find what it consumes and provides.  Providers
+// that cannot be characterized provide nothing: the error will be
+// reported when the collection is characterized for real.
+func providerFlows(fm *provider) (inputs map[typeCode]struct{}, outputs map[typeCode]struct{}) {
+	cfm, err := characterizeFunc(fm, charContext{inputsAreStatic: true})
+	if err != nil {
+		return nil, nil
+	}
+	inputs = make(map[typeCode]struct{})
+	for _, tc := range cfm.flows[inputParams] {
+		inputs[tc] = struct{}{}
+	}
+	outputs = make(map[typeCode]struct{})
+	for _, tc := range cfm.flows[outputParams] {
+		if tc != noTypeCode && tc != unusedTypeCode {
+			outputs[tc] = struct{}{}
+		}
+	}
+	return inputs, outputs
+}
+
+// providerOutputs returns what providerFlows finds that fm provides
+func providerOutputs(fm *provider) map[typeCode]struct{} {
+	_, outputs := providerFlows(fm)
+	return outputs
+}
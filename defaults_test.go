@@ -0,0 +1,163 @@
+package nject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultUsed(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got s1
+		require.NoError(t, Run(t.Name(),
+			Default(func() s1 { return "default" }),
+			func(s s1) { got = s },
+		))
+		assert.Equal(t, s1("default"), got)
+	})
+}
+
+func TestDefaultOverridden(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got s1
+		var defaultCalled bool
+		require.NoError(t, Run(t.Name(),
+			Default(func() s1 { defaultCalled = true; return "default" }),
+			Required(func(s s1) s2 { got = s; return s2(s) }),
+			Override(func() s1 { return "override" }),
+			func() {},
+		))
+		assert.Equal(t, s1("override"), got, "override moved before its consumer")
+		assert.False(t, defaultCalled)
+	})
+}
+
+func TestDefaultOtherProviderAnywhere(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got s1
+		var defaultCalled bool
+		require.NoError(t, Run(t.Name(),
+			func() s1 { return "other" },
+			Required(func(s s1) s2 { got = s; return s2(s) }),
+			Default(func() s1 { defaultCalled = true; return "default" }),
+			func() {},
+		))
+		assert.Equal(t, s1("other"), got, "default after the other provider is still dropped")
+		assert.False(t, defaultCalled)
+	})
+}
+
+func TestDefaultOtherProviderLater(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got s1
+		var defaultCalled, betweenCalled bool
+		require.NoError(t, Run(t.Name(),
+			Default(func() s1 { defaultCalled = true; return "default" }),
+			Provide("between", Desired(func(_ s1) { betweenCalled = true })),
+			func() s1 { return "other" },
+			func(s s1) { got = s },
+		))
+		assert.Equal(t, s1("other"), got)
+		assert.False(t, defaultCalled)
+		assert.False(t, betweenCalled, "between cannot use the default")
+
+		err := Run(t.Name(),
+			Default(func() s1 { return "default" }),
+			Provide("between", Required(func(s s1) s2 { return s2(s) })),
+			func() s1 { return "other" },
+			func(_ s1) {},
+		)
+		require.Error(t, err, "between cannot use the default because another provider of s1 is included")
+		assert.Contains(t, err.Error(), "between")
+
+		got = ""
+		require.NoError(t, Run(t.Name(),
+			Default(func() s1 { return "default" }),
+			Required(func(s s1) s2 { got = s; return s2(s) }),
+			func(_ s3) s1 { return "other" },
+			func() {},
+		))
+		assert.Equal(t, s1("default"), got, "the later provider cannot be included")
+	})
+}
+
+func TestDefaultWithModifier(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got s1
+		require.NoError(t, Run(t.Name(),
+			Default(func() s1 { return "default" }),
+			func(s s1) s1 { return s + "-modified" },
+			func(s s1) { got = s },
+		))
+		assert.Equal(t, s1("default-modified"), got, "a modifier is not another provider")
+	})
+}
+
+func TestDefaultOtherProviderExcluded(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got s1
+		require.NoError(t, Run(t.Name(),
+			func(s s2) s1 { return s1(s) },
+			Default(func() s1 { return "default" }),
+			func(s s1) { got = s },
+		))
+		assert.Equal(t, s1("default"), got, "the other provider cannot be included")
+
+		var invoke func()
+		warnings := Lint(Sequence(t.Name(),
+			func(s s2) s1 { return s1(s) },
+			Default(func() s1 { return "default" }),
+			func(s s1) { got = s },
+		), &invoke, nil)
+		assert.Empty(t, lintKinds(warnings)[WarnShunIncluded])
+	})
+}
+
+func TestDefaultCollection(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got1 s1
+		var got2 s2
+		require.NoError(t, Run(t.Name(),
+			Default(Sequence("defaults",
+				func() s1 { return "default" },
+				func() s2 { return "default" },
+			)),
+			Override(func() s2 { return "override" }),
+			func(a s1, b s2) {
+				got1 = a
+				got2 = b
+			},
+		))
+		assert.Equal(t, s1("default"), got1)
+		assert.Equal(t, s2("override"), got2)
+	})
+}
+
+func TestOverrideWithoutDefault(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		err := Run(t.Name(),
+			Default(func() s1 { return "default" }),
+			Provide("lonely", Override(func() s2 { return "override" })),
+			func(_ s1, _ s2) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lonely [")
+		assert.Contains(t, err.Error(), "is an Override but there is no Default for nject/v2.s2")
+	})
+}
+
+func TestOverridesConflict(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		err := Run(t.Name(),
+			Default(func() s1 { return "default" }),
+			Provide("override-1", Override(func() s1 { return "one" })),
+			Provide("override-2", Override(func() s1 { return "two" })),
+			func(_ s1) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "override-1 [")
+		assert.Contains(t, err.Error(), "override-2 [")
+		assert.Contains(t, err.Error(), "are both Overrides of nject/v2.s1")
+	})
+}
@@ -348,11 +348,15 @@ Only include if all outputs are transitively consumed by a required
 or Desired chain element.
 
 When there are multiple providers of a type, Bind() tries to get it
-from the closest provider.
+from the closest provider.  Mark a provider Exclusive to make it an
+error for there to be another provider of its type.
 
 Providers that have unmet dependencies will be eliminated from the chain
 unless they're Required.
 
+Providers that are marked Strict are not forced into the chain but if they
+are eliminated, Bind() fails and reports why they were eliminated.
+
 # Best practices
 
 The remainder of this document consists of suggestions for how to use nject.
@@ -424,10 +428,11 @@ Remove the comments to hide the original type names.
 # Reorder
 
 The Reorder() decorator allows injection chains to be fully or partially reordered.
-Reorder is currently limited to a single pass and does not know which injectors are
-ultimately going to be included in the final chain. It is likely that if you mark
-your entire chain with Reorder, you'll have unexpected results.  On the other hand,
-Reorder provides safe and easy way to solve some common problems.
+Reordering and deciding which injectors to include are repeated until the set of
+included injectors stops changing so that the placement of the reorderable
+injectors only depends upon the injectors that are in the final chain.  Entire
+collections can be marked with Reorder.  Reorder provides safe and easy way to
+solve some common problems.
 
 For example: providing optional options to an injected dependency.
 
@@ -463,6 +468,70 @@ and used.
 		OverrideThingOptions(thing.Option1, thing.Option2),
 	)
 
+Default() and Override() do the same thing with checking: the Default is
+dropped if there is an Override (or any other included provider of the
+same type) anywhere in the chain and the Override takes the place of the
+Default.
+It is an error to have an Override without a Default or to have two
+Overrides of the same type.
+
+	var ThingChain = nject.Sequence("thingChain",
+		nject.Default(DefaultThingOptions),
+		ThingProvider,
+	)
+
+	func OverrideThingOptions(options ...ThingOption) nject.Provider {
+		return nject.Override(func() []ThingOption {
+			return options
+		})
+	}
+
+After() and Before() add ordering constraints that do not depend upon data
+flows.  They name the injectors (see Provide) that the annotated injector must
+run after or before.  They imply Reorder.  Contradictory constraints cause
+Bind to fail.
+
+	nject.Run("request",
+		nject.Required(nject.After("authenticate", RecordMetrics)),
+		nject.Provide("authenticate", Authenticate),
+		HandleRequest,
+	)
+
+AutoDeref(), AutoAddr(), and Convert() add conversions between types.  After
+each injector that provides the type being converted from, a Shun conversion
+injector is added.  The conversion is only used when nothing else provides
+the type being converted to.
+
+	nject.Run("request",
+		nject.AutoDeref[Config](),
+		nject.Convert(func(id UserID) string { return string(id) }),
+		LoadConfig,    // provides *Config
+		HandleRequest, // wants Config
+	)
+
+As() provides a value under an additional type, usually an interface.
+
+	nject.As[io.Reader](OpenFile) // provides *os.File and io.Reader
+
+Spread() provides the fields of a returned struct as separate values.  Structs
+that embed nject.Out are spread without Spread().
+
+	type Clients struct {
+		nject.Out
+		DB    *sql.DB `nject:"mustconsume"`
+		Cache *redis.Client
+	}
+
+Parameter structs that embed nject.In are received field by field.  Fields
+tagged "optional" are left empty when nothing earlier in the chain provides
+their type.  Optional fields never cause a provider to be included.
+
+	type HandlerDeps struct {
+		nject.In
+		DB     *sql.DB
+		Logger *slog.Logger
+	}
+
 # Self-cleaning
 
 Recommended best practice is to have injectors shutdown the things they themselves start. They
@@ -42,6 +42,15 @@ var (
 	duplicatesFound   = make(map[string]struct{})
 )
 
+// isDuplicateTypeName returns true if more than one type has the name
+func isDuplicateTypeName(name string) bool {
+	_ = duplicateTypes()
+	dupLock.Lock()
+	defer dupLock.Unlock()
+	_, ok := duplicatesFound[name]
+	return ok
+}
+
 func duplicateTypes() string {
 	maxTC := func() int {
 		lock.Lock()
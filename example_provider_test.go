@@ -21,8 +21,8 @@ func ExampleProvide() {
 			return 4
 		}),
 	))
-	// Output: final-func: failure1(0) [func(string) int]: required but has no match for its input parameter string
-	// final-func: create-int [func(string) int]: required but has no match for its input parameter string
+	// Output: final-func: failure1(0) [func(string) int] (registered example_provider_test.go:14, defined example_provider_test.go:16): required but has no match for its input parameter string
+	// final-func: failure2/create-int [func(string) int] (registered example_provider_test.go:20, defined example_provider_test.go:21): required but has no match for its input parameter string
 }
 
 func ExampleProvide_literal() {
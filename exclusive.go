@@ -0,0 +1,69 @@
+package nject
+
+import "fmt"
+
+// checkExclusive makes sure that the types that providers have marked
+// Exclusive are not provided by any other included provider.  Exclusive
+// providers that were excluded only because they were not needed are
+// checked too: they may not be needed because another provider of the
+// type was closer.
+func checkExclusive(funcs []*provider) error {
+	var providers map[typeCode][]*provider
+	for _, fm := range funcs {
+		if len(fm.exclusive) == 0 || !fm.couldInclude() {
+			continue
+		}
+		if providers == nil {
+			providers = providersByType(funcs)
+		}
+		for tc := range fm.exclusive {
+			if !providesType(fm, tc) {
+				// fm doesn't provide tc so it isn't exclusive
+				continue
+			}
+			for _, other := range providers[tc] {
+				if other != fm {
+					return fmt.Errorf("%s provides %s which is Exclusive to %s", other, tc, fm)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// providersByType maps types to the included providers that provide
+// them.  Pass-through providers that receive the same type are skipped.
+func providersByType(funcs []*provider) map[typeCode][]*provider {
+	providers := make(map[typeCode][]*provider)
+	for _, fm := range funcs {
+		if !fm.include || fm.isSynthetic {
+			continue
+		}
+		received := make(map[typeCode]bool)
+		for _, tc := range fm.flows[inputParams] {
+			received[tc] = true
+		}
+		for _, tc := range fm.flows[outputParams] {
+			if tc == noTypeCode || tc == unusedTypeCode || received[tc] {
+				continue
+			}
+			providers[tc] = append(providers[tc], fm)
+		}
+	}
+	return providers
+}
+
+// providesType is true if fm provides tc and does not receive it
+func providesType(fm *provider, tc typeCode) bool {
+	for _, in := range fm.flows[inputParams] {
+		if in == tc {
+			return false
+		}
+	}
+	for _, out := range fm.flows[outputParams] {
+		if out == tc {
+			return true
+		}
+	}
+	return false
+}
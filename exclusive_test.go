@@ -0,0 +1,80 @@
+package nject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExclusiveOnlyProvider(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var called bool
+		require.NoError(t, Run(t.Name(),
+			Exclusive[s1](func() s1 { return "s1" }),
+			// pass-through providers don't count
+			func(s s1) s1 { return s + "!" },
+			func(s s1) {
+				assert.Equal(t, s1("s1!"), s)
+				called = true
+			},
+		))
+		assert.True(t, called)
+	})
+}
+
+func TestExclusiveConflict(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		err := Run(t.Name(),
+			Provide("first", Exclusive[s1](func() s1 { return "s1" })),
+			Provide("middle", func(s s1) s2 { return s2(s) }),
+			Provide("second", func() s1 { return "other s1" }),
+			func(_ s1, _ s2) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "second [")
+		assert.Contains(t, err.Error(), "which is Exclusive to")
+		assert.Contains(t, err.Error(), "first [")
+	})
+}
+
+func TestExclusiveExcludedByCloser(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		err := Run(t.Name(),
+			Provide("first", Exclusive[s1](func() s1 { return "s1" })),
+			Provide("second", func() s1 { return "other s1" }),
+			func(_ s1) {},
+		)
+		require.Error(t, err, "first is excluded because second is closer")
+		assert.Contains(t, err.Error(), "second [")
+		assert.Contains(t, err.Error(), "which is Exclusive to")
+		assert.Contains(t, err.Error(), "first [")
+	})
+}
+
+func TestExclusiveCannotIncludeIsOkay(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		require.NoError(t, Run(t.Name(),
+			Provide("first", Exclusive[s1](func(_ s2) s1 { return "s1" })),
+			Provide("second", func() s1 { return "other s1" }),
+			func(_ s1) {},
+		), "first cannot be included so it doesn't matter")
+	})
+}
+
+func TestExclusiveCollection(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		err := Run(t.Name(),
+			Exclusive[s1](Sequence("shared",
+				s0("s0"),
+				Provide("shared-s1", func(s s0) s1 { return s1(s) }),
+			)),
+			Provide("user-s2", func(s s1) s2 { return s2(s) }),
+			Provide("local-s1", func() s1 { return "local" }),
+			func(_ s1, _ s2) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "local-s1")
+		assert.Contains(t, err.Error(), "shared-s1")
+	})
+}
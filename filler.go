@@ -2,8 +2,11 @@ package nject
 
 import (
 	"fmt"
+	"os"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 )
@@ -13,17 +16,26 @@ const debugFiller = false
 // filler tracks how to fill a struct, it is an example implementation
 // of Reflective
 type filler struct {
-	pointer bool
-	copy    bool
-	typ     reflect.Type
-	inputs  []inputDisposition
+	pointer   bool
+	copy      bool
+	fallible  bool // returns TerminalError for env parse errors
+	typ       reflect.Type
+	inputs    []inputDisposition
+	fallbacks []inputDisposition // optional inputs not received that have env or default
+	allocate  [][]int            // pointer fields to allocate for "build", outermost first
 }
 
 // inputDisposition is how one input to Call() gets stored
 // into a struct
 type inputDisposition struct {
-	mapping []int
-	typ     reflect.Type
+	mapping      []int
+	typ          reflect.Type
+	name         string
+	optional     bool
+	env          string // from "env=NAME"
+	defaultValue string // from "default=value"
+	hasDefault   bool
+	envLookup    bool // input is the EnvLookup
 }
 
 var _ Reflective = &filler{}
@@ -81,7 +93,8 @@ func getInZero(cc canCall) (reflect.Type, bool) {
 
 type fillerOptions struct {
 	tag              string
-	postMethodName   []string
+	validateTag      string
+	postMethods      []postMethod
 	postActionByTag  map[string]postActionOption
 	postActionByName map[string]postActionOption
 	postActionByType []postActionOption
@@ -89,14 +102,16 @@ type fillerOptions struct {
 }
 
 var reservedTags = map[string]struct{}{
-	"whole":  {},
-	"blob":   {},
-	"fields": {},
-	"field":  {},
-	"-":      {},
-	"skip":   {},
-	"nofill": {},
-	"fill":   {},
+	"whole":    {},
+	"blob":     {},
+	"fields":   {},
+	"field":    {},
+	"-":        {},
+	"skip":     {},
+	"nofill":   {},
+	"fill":     {},
+	"optional": {},
+	"build":    {},
 }
 
 // MakeStructBuilder generates a Provider that wants to receive as
@@ -134,6 +149,37 @@ var reservedTags = map[string]struct{}{
 // behavior.  "fill" overrides other behaviors including defaults set with
 // post-actions.
 //
+// "optional": if nothing earlier in the chain provides the exact type of
+// the field, then the field is left as the zero value.  Optional fields
+// are only filled by providers that are included in the chain for other
+// reasons: they never cause a provider to be included.  On an embedded struct that is filled field-by-field,
+// all of its fields are optional.
+//
+// "build": the field, which must be a struct or a pointer to a struct,
+// is built field-by-field with the same rules, tags, and options
+// (including post-actions) as the outer struct.  Pointers are allocated
+// if they are nil.  Structs that are not embedded are also built
+// field-by-field by default (see "fields") but pointers are not.
+//
+// "default=value": if the field is not provided, value is parsed and
+// used instead.  Strings, bools, numbers, time.Duration, and slices of
+// those can be parsed.  Slice elements are separated with semicolons.
+// An invalid default is an error from MakeStructBuilder.  Fields with
+// defaults are optional.
+//
+// "env=NAME": if the field is not provided and the environment variable
+// NAME is set, its value is parsed and used.  The environment variable is
+// preferred over the default.  The environment is read with the EnvLookup
+// provided earlier in the chain, or os.LookupEnv if there isn't one.
+// Like an optional field, the EnvLookup does not cause its provider to
+// be included.
+// The field must have a type that default could parse; if not, that is
+// an error from MakeStructBuilder.  If the value cannot be parsed, the
+// builder returns a TerminalError.  Fields with env are optional.
+//
+// With WithValidateTag, fields are also checked with validation rules
+// from their struct tags.
+//
 // If you just want to provide a value variable, use FillVars() instead.
 func MakeStructBuilder(model any, optArgs ...FillerFuncArg) (Provider, error) {
 	// Options handling
@@ -199,8 +245,9 @@ func MakeStructBuilder(model any, optArgs ...FillerFuncArg) (Provider, error) {
 	// Field handling.  A closure so that it can be invoked recursively
 	// since that's how you have to traverse nested structures.
 	var additionalReflectives []any
-	var mapStruct func(t reflect.Type, path []int) error
-	mapStruct = func(t reflect.Type, path []int) error {
+	building := map[reflect.Type]bool{t: true}
+	var mapStruct func(t reflect.Type, path []int, optional bool) error
+	mapStruct = func(t reflect.Type, path []int, optional bool) error {
 		for i := 0; i < t.NumField(); i++ {
 			field := t.Field(i)
 			np := copyIntSlice(path)
@@ -216,6 +263,11 @@ func MakeStructBuilder(model any, optArgs ...FillerFuncArg) (Provider, error) {
 			var noSkip bool
 			var whole bool
 			var hardSkip bool
+			var build bool
+			optional := optional
+			var env string
+			var defaultValue string
+			var hasDefault bool
 			handleFieldFiller := func(fun postActionOption, description string) error {
 				if hardSkip {
 					return nil
@@ -250,6 +302,18 @@ func MakeStructBuilder(model any, optArgs ...FillerFuncArg) (Provider, error) {
 						case "-", "skip":
 							skip = true
 							hardSkip = true
+						case "optional":
+							optional = true
+						case "build":
+							st := field.Type
+							if st.Kind() == reflect.Ptr {
+								st = st.Elem()
+							}
+							if st.Kind() != reflect.Struct {
+								return fmt.Errorf("cannot use tag %s on type %s (%s) for building struct filler",
+									tv, field.Name, field.Type)
+							}
+							build = true
 						case "whole", "blob":
 							if field.Type.Kind() == reflect.Struct {
 								whole = true
@@ -265,6 +329,15 @@ func MakeStructBuilder(model any, optArgs ...FillerFuncArg) (Provider, error) {
 									tv, field.Name, field.Type)
 							}
 						default:
+							if strings.HasPrefix(tv, "default=") {
+								defaultValue = strings.TrimPrefix(tv, "default=")
+								hasDefault = true
+								continue
+							}
+							if strings.HasPrefix(tv, "env=") {
+								env = strings.TrimPrefix(tv, "env=")
+								continue
+							}
 							// PostActionByTag
 							if fun, ok := options.postActionByTag[tv]; ok {
 								err := handleFieldFiller(fun, fmt.Sprintf(
@@ -302,11 +375,53 @@ func MakeStructBuilder(model any, optArgs ...FillerFuncArg) (Provider, error) {
 					}
 				}
 			}
+			if options.validateTag != "" && !hardSkip {
+				if rules := field.Tag.Get(options.validateTag); rules != "" {
+					validator, err := makeValidator(field.Type, rules)
+					if err != nil {
+						return fmt.Errorf("invalid %s tag on %s: %w", options.validateTag, field.Name, err)
+					}
+					ap, _, err := addFieldFiller(np, &field, originalType, postActionOption{
+						function:         validator,
+						matchToInterface: true,
+					}, fmt.Sprintf("validate %s for %s", field.Name, originalType))
+					if err != nil {
+						return err
+					}
+					additionalReflectives = append(additionalReflectives, ap)
+				}
+			}
+
 			if skip {
 				continue
 			}
+			if build {
+				if hasDefault || env != "" {
+					return fmt.Errorf("cannot use default or env on %s (%s) because it is built",
+						field.Name, field.Type)
+				}
+				st := field.Type
+				if st.Kind() == reflect.Ptr {
+					st = st.Elem()
+					f.allocate = append(f.allocate, np)
+				}
+				if building[st] {
+					return fmt.Errorf("cannot build %s (%s) because %s is already being built", field.Name, field.Type, st)
+				}
+				building[st] = true
+				err := mapStruct(st, np, optional)
+				delete(building, st)
+				if err != nil {
+					return err
+				}
+				continue
+			}
 			if field.Type.Kind() == reflect.Struct && !whole {
-				err := mapStruct(field.Type, np)
+				if hasDefault || env != "" {
+					return fmt.Errorf("cannot use default or env on %s (%s) because it is filled field-by-field",
+						field.Name, field.Type)
+				}
+				err := mapStruct(field.Type, np, optional)
 				if err != nil {
 					return err
 				}
@@ -315,17 +430,42 @@ func MakeStructBuilder(model any, optArgs ...FillerFuncArg) (Provider, error) {
 			if debugFiller {
 				fmt.Printf(" map input %d (%s) to %s %v\n", len(f.inputs), field.Type.String(), field.Name, np)
 			}
+			if hasDefault {
+				_, err := parseFieldValue(field.Type, defaultValue)
+				if err != nil {
+					return fmt.Errorf("invalid default for %s: %w", field.Name, err)
+				}
+			}
+			if env != "" {
+				if !canParseFieldValue(field.Type) {
+					return fmt.Errorf("cannot use env on %s because %s cannot be parsed", field.Name, field.Type)
+				}
+				f.fallible = true
+			}
 			f.inputs = append(f.inputs, inputDisposition{
-				typ:     field.Type,
-				mapping: np,
+				typ:          field.Type,
+				mapping:      np,
+				name:         field.Name,
+				optional:     optional || hasDefault || env != "",
+				env:          env,
+				defaultValue: defaultValue,
+				hasDefault:   hasDefault,
 			})
 		}
 		return nil
 	}
-	err := mapStruct(t, []int{})
+	err := mapStruct(t, []int{}, false)
 	if err != nil {
 		return nil, err
 	}
+	if f.fallible {
+		f.inputs = append(f.inputs, inputDisposition{
+			typ:       envLookupType,
+			name:      "EnvLookup",
+			optional:  true,
+			envLookup: true,
+		})
+	}
 
 	// Build the Provider/Cluster
 	p := Provide(fmt.Sprintf("builder for %T", model), &f)
@@ -335,12 +475,24 @@ func MakeStructBuilder(model any, optArgs ...FillerFuncArg) (Provider, error) {
 	}
 	chain = append(chain, p)
 	chain = append(chain, additionalReflectives...)
-	for _, name := range options.postMethodName {
-		m, err := generatePostMethod(originalType, name)
+	for _, pm := range options.postMethods {
+		m, err := generatePostMethod(originalType, pm)
 		if err != nil {
 			return nil, err
 		}
-		chain = append(chain, m)
+		if pm.optional {
+			found := "not found, skipped"
+			switch {
+			case m != nil:
+				found = "found"
+			case onlyOnPointer(originalType, pm.name):
+				found = "only defined on the pointer receiver, skipped"
+			}
+			chain = append(chain, newNote(fmt.Sprintf("WithOptionalMethod(%s) on %s: %s", pm.name, originalType, found)))
+		}
+		if m != nil {
+			chain = append(chain, m)
+		}
 	}
 	if len(chain) == 1 {
 		return p, nil
@@ -348,6 +500,34 @@ func MakeStructBuilder(model any, optArgs ...FillerFuncArg) (Provider, error) {
 	return Cluster(fmt.Sprintf("builder seq for %T", model), chain...), nil
 }
 
+func (f *filler) optionalTypes() map[reflect.Type]string {
+	optional := make(map[reflect.Type]string)
+	for _, input := range f.inputs {
+		if input.optional {
+			optional[input.typ] = input.name
+		}
+	}
+	return optional
+}
+
+// withoutOptional returns a filler that does not receive the
+// optional inputs whose types are missing
+func (f *filler) withoutOptional(missing map[reflect.Type]struct{}) any {
+	nf := *f
+	nf.inputs = make([]inputDisposition, 0, len(f.inputs))
+	nf.fallbacks = append([]inputDisposition(nil), f.fallbacks...)
+	for _, input := range f.inputs {
+		if _, ok := missing[input.typ]; ok && input.optional {
+			if input.env != "" || input.hasDefault {
+				nf.fallbacks = append(nf.fallbacks, input)
+			}
+			continue
+		}
+		nf.inputs = append(nf.inputs, input)
+	}
+	return &nf
+}
+
 func (f *filler) In(i int) reflect.Type {
 	return f.inputs[i].typ
 }
@@ -356,14 +536,20 @@ func (f *filler) NumIn() int {
 	return len(f.inputs)
 }
 
-func (f *filler) Out(_ int) reflect.Type {
+func (f *filler) Out(i int) reflect.Type {
+	if i == 1 {
+		return terminalErrorType
+	}
 	if f.pointer {
 		return reflect.PointerTo(f.typ)
 	}
 	return f.typ
 }
 
-func (*filler) NumOut() int {
+func (f *filler) NumOut() int {
+	if f.fallible {
+		return 2
+	}
 	return 1
 }
 
@@ -394,8 +580,21 @@ func (f *filler) Call(inputs []reflect.Value) []reflect.Value {
 			r = v
 		}
 	}
+	for _, path := range f.allocate {
+		pv := v.FieldByIndex(path)
+		if pv.IsNil() {
+			pv.Set(reflect.New(pv.Type().Elem()))
+		}
+	}
+	lookup := os.LookupEnv
 	for i, input := range inputs {
 		disposition := f.inputs[i]
+		if disposition.envLookup {
+			if l, ok := input.Interface().(EnvLookup); ok && l != nil {
+				lookup = l
+			}
+			continue
+		}
 		if disposition.mapping == nil {
 			continue
 		}
@@ -407,7 +606,119 @@ func (f *filler) Call(inputs []reflect.Value) []reflect.Value {
 		}
 		fv.Set(input)
 	}
-	return []reflect.Value{r}
+	err := f.fillFallbacks(v, lookup)
+	if !f.fallible {
+		return []reflect.Value{r}
+	}
+	var te TerminalError
+	if err != nil {
+		te = err
+	}
+	return []reflect.Value{r, reflect.ValueOf(&te).Elem()}
+}
+
+// fillFallbacks fills the fields that were not provided from the
+// environment or from their defaults.  When filling an existing
+// struct, only zero fields are filled.
+func (f *filler) fillFallbacks(v reflect.Value, lookup EnvLookup) error {
+	for _, fb := range f.fallbacks {
+		fv := v.FieldByIndex(fb.mapping)
+		if f.copy && !fv.IsZero() {
+			continue
+		}
+		if fb.env != "" {
+			if s, ok := lookup(fb.env); ok {
+				pv, err := parseFieldValue(fb.typ, s)
+				if err != nil {
+					return fmt.Errorf("cannot set %s from environment variable %s: %w", fb.name, fb.env, err)
+				}
+				fv.Set(pv)
+				continue
+			}
+		}
+		if fb.hasDefault {
+			// already validated by MakeStructBuilder
+			pv, _ := parseFieldValue(fb.typ, fb.defaultValue)
+			fv.Set(pv)
+		}
+	}
+	return nil
+}
+
+// parseFieldValue parses a string for the "default" and "env" tags
+func parseFieldValue(t reflect.Type, s string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	if t == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(int64(d))
+		return v, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s, 0, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(x)
+	case reflect.Slice:
+		if s == "" {
+			v.Set(reflect.MakeSlice(t, 0, 0))
+			return v, nil
+		}
+		parts := strings.Split(s, ";")
+		v.Set(reflect.MakeSlice(t, len(parts), len(parts)))
+		for i, part := range parts {
+			ev, err := parseFieldValue(t.Elem(), part)
+			if err != nil {
+				return v, err
+			}
+			v.Index(i).Set(ev)
+		}
+	default:
+		return v, fmt.Errorf("cannot parse %s", t)
+	}
+	return v, nil
+}
+
+// canParseFieldValue reports if parseFieldValue can parse values of type t
+func canParseFieldValue(t reflect.Type) bool {
+	if t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return canParseFieldValue(t.Elem())
+	default:
+		return false
+	}
 }
 
 func copyIntSlice(in []int) []int {
@@ -487,13 +798,14 @@ func addFieldFiller(
 	if option.matchToInterface && countEmptyInterfaces != 1 {
 		return nil, false, fmt.Errorf("%s need exactly one any parameter in function", context)
 	}
-	if score == bad {
+	if !option.matchToInterface && score == bad {
 		return nil, false, fmt.Errorf("%s no match found between field type %s and function inputs",
 			context, field.Type)
 	}
 	targetType := inputs[inputIndex]
 	inputs[inputIndex] = outerStruct
 	structIsPtr := outerStruct.Kind() == reflect.Ptr
+	fieldName := fieldPath(outerStruct, path)
 	if needConvert && addressOf {
 		return nil, false, fmt.Errorf(" %s, matched %s to input %s (converting) but that cannot be combined with conversion to a pointer",
 			context, field.Type, targetType)
@@ -518,14 +830,59 @@ func addFieldFiller(
 					v = v.Convert(targetType)
 				}
 				in[inputIndex] = v
-				return funcAsValue.Call(in)
+				out := funcAsValue.Call(in)
+				for i, o := range out {
+					if t.Out(i) == terminalErrorType && !o.IsNil() {
+						// name the field in the error
+						te := TerminalError(fmt.Errorf("%s: %w", fieldName, o.Interface().(error)))
+						out[i] = reflect.ValueOf(&te).Elem()
+					}
+				}
+				return out
 			},
 		}), addressOf, nil
 }
 
-func generatePostMethod(modelType reflect.Type, methodName string) (Provider, error) {
+// fieldPath returns the dotted names of the fields in path
+func fieldPath(t reflect.Type, path []int) string {
+	names := make([]string, len(path))
+	for i, index := range path {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		field := t.Field(index)
+		names[i] = field.Name
+		t = field.Type
+	}
+	return strings.Join(names, ".")
+}
+
+// onlyOnPointer reports if modelType is not a pointer and the method
+// is only defined on the pointer receiver.  Such methods are not called
+// because they would be called on a copy and any changes would be lost.
+func onlyOnPointer(modelType reflect.Type, methodName string) bool {
+	if modelType.Kind() == reflect.Ptr {
+		return false
+	}
+	if _, ok := modelType.MethodByName(methodName); ok {
+		return false
+	}
+	_, ok := reflect.PointerTo(modelType).MethodByName(methodName)
+	return ok
+}
+
+// generatePostMethod returns nil if the method is optional and does
+// not exist.
+func generatePostMethod(modelType reflect.Type, pm postMethod) (Provider, error) {
+	methodName := pm.name
 	method, ok := modelType.MethodByName(methodName)
 	if !ok {
+		if pm.optional {
+			return nil, nil
+		}
+		if onlyOnPointer(modelType, methodName) {
+			return nil, fmt.Errorf("WithPostMethod(%s) on %s: the method has a pointer receiver so the struct must be built as a pointer", methodName, modelType)
+		}
 		return nil, fmt.Errorf("WithPostMethod(%s) on %s: no such method exists", methodName, modelType)
 	}
 	desc := fmt.Sprintf("%s.%s()", modelType, methodName)
@@ -69,14 +69,47 @@ func WithTag(tag string) FillerFuncArg {
 	}
 }
 
-// TODO: WithOptionalMethod
+// WithValidateTag turns on validation in MakeStructBuilder and sets the
+// struct tag to use for the validation rules, for example
+// WithValidateTag("validate").  Validation is off by default so that
+// tags meant for other validation libraries are not misread.  The
+// rules are separated by commas:
+//
+// "required": the field must not be the zero value.
+//
+// "min=N" & "max=N": for strings, slices, maps, arrays, and channels,
+// the length must be at least/most N.  For numbers (including
+// time.Duration) the value must be at least/most N.
+//
+// "oneof=a b c": the value must be one of the space-separated values.
+//
+// The rules are checked by post-actions that are added after any other
+// post-actions for the field.  If a rule is not met, the builder chain
+// returns a TerminalError that names the field.  Invalid rules are an
+// error from MakeStructBuilder.
+func WithValidateTag(tag string) FillerFuncArg {
+	return func(o *fillerOptions) {
+		o.validateTag = tag
+	}
+}
+
+// EnvLookup is used by MakeStructBuilder to read environment variables
+// for fields tagged "env=NAME".  If an EnvLookup is provided earlier in
+// the injection chain, it is used instead of os.LookupEnv.  Since
+// an EnvLookup is a function, provide it with a function that
+// returns it.  The builder does not cause the provider to be included
+// so unless something else consumes the EnvLookup, mark it Required:
+//
+//	nject.Required(func() nject.EnvLookup { return lookup })
+type EnvLookup func(name string) (string, bool)
 
 // WithMethodCall looks up a method on the struct being
 // filled or built and adds a method invocation to the
 // dependency chain.  The method can be any kind of function
 // provider (the last function, a wrapper, etc).  If there
 // is no method of that name, then MakeStructBuilder will
-// return an error.
+// return an error.  Methods with a pointer receiver can only
+// be called when the struct is built as a pointer.
 //
 // EXPERIMENTAL: this is currently considered experimental
 // and could be removed in a future release.  If you are using
@@ -87,7 +120,27 @@ func WithMethodCall(methodName string) FillerFuncArg {
 	// the version of the method that takes an explicit
 	// receiver.
 	return func(o *fillerOptions) {
-		o.postMethodName = append(o.postMethodName, methodName)
+		o.postMethods = append(o.postMethods, postMethod{name: methodName})
+	}
+}
+
+type postMethod struct {
+	name     string
+	optional bool
+}
+
+// WithOptionalMethod is like WithMethodCall except that if there is
+// no method of that name, it is skipped rather than being an error.
+// This allows generic builders to call methods like Validate() or
+// Init() on just the types that have them.  When the struct is built
+// as a value and the method is only defined on the pointer receiver,
+// the method is skipped since calling it on a copy would lose any
+// changes it makes.
+//
+// Whether the method was found is recorded in the Debugging trace.
+func WithOptionalMethod(methodName string) FillerFuncArg {
+	return func(o *fillerOptions) {
+		o.postMethods = append(o.postMethods, postMethod{name: methodName, optional: true})
 	}
 }
 
@@ -101,9 +154,10 @@ func WithMethodCall(methodName string) FillerFuncArg {
 // function that builds or fills the struct.  If there is also a
 // WithMethodCall, this function will run before that.
 //
-// EXPERIMENTAL: this is currently considered experimental
-// and could be removed in a future release.  If you are using
-// this, please open a pull request to remove this comment.
+// Post-action functions (for PostActionByTag, PostActionByName, and
+// PostActionByType) may return TerminalError.  If they do and the error
+// is not nil, the chain is aborted and the error is prefixed with the
+// path of the field, for example "DB.Pool.Size: ...".
 func PostActionByTag(tagValue string, function any, opts ...PostActionFuncArg) FillerFuncArg {
 	// Implementation note:
 	// There could be more than one field using the same type so
@@ -128,10 +182,6 @@ func PostActionByTag(tagValue string, function any, opts ...PostActionFuncArg) F
 //
 // If there is no match to the type of the function, then the function
 // is not invoked.
-//
-// EXPERIMENTAL: this is currently considered experimental
-// and could be removed in a future release.  If you are using
-// this, please open a pull request to remove this comment.
 func PostActionByType(function any, opts ...PostActionFuncArg) FillerFuncArg {
 	options := makePostActionOption(function, opts)
 	return func(o *fillerOptions) {
@@ -142,10 +192,6 @@ func PostActionByType(function any, opts ...PostActionFuncArg) FillerFuncArg {
 // PostActionByName arranges to call a function passing in the field that
 // has a matching name.  PostActionByName happens before PostActionByType
 // and after PostActionByTag calls.
-//
-// EXPERIMENTAL: this is currently considered experimental
-// and could be removed in a future release.  If you are using
-// this, please open a pull request to remove this comment.
 func PostActionByName(name string, function any, opts ...PostActionFuncArg) FillerFuncArg {
 	options := makePostActionOption(function, opts)
 	return func(o *fillerOptions) {
@@ -1,9 +1,12 @@
 package nject
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type FillStruct struct {
@@ -75,3 +78,395 @@ func TestFiller(t *testing.T) {
 	assert.NoError(t, err) //nolint:testifylint // okay to keep going
 	assert.True(t, called)
 }
+
+type FillOptional struct {
+	S0  s0 `nject:"optional"`
+	S1  s1 `nject:"optional"`
+	S2  s2 `nject:"optional"`
+	S3  s3
+	Sub struct {
+		S4 s4
+	} `nject:"optional"`
+}
+
+func TestFillerOptional(t *testing.T) {
+	t.Parallel()
+	var called bool
+	err := Run("TestFillerOptional",
+		Required(s0("s0")),
+		func(_ s5) s1 { return "cannot be included" },
+		Shun(func() s2 { return "shunned" }),
+		s3("s3"),
+		MustMakeStructBuilder(FillOptional{}),
+		func(f FillOptional) {
+			called = true
+			assert.Equal(t, s0("s0"), f.S0, "provided")
+			assert.Equal(t, s1(""), f.S1, "provider cannot be included")
+			assert.Equal(t, s2(""), f.S2, "provider is shunned")
+			assert.Equal(t, s3("s3"), f.S3, "required")
+			assert.Equal(t, s4(""), f.Sub.S4, "not provided")
+		},
+	)
+	assert.NoError(t, err) //nolint:testifylint // okay to keep going
+	assert.True(t, called)
+
+	err = Run("TestFillerOptional-required",
+		s0("s0"),
+		MustMakeStructBuilder(FillOptional{}),
+		func(_ FillOptional) {},
+	)
+	assert.Error(t, err, "s3 is not optional")
+}
+
+type FillDefaults struct {
+	S0      s0            `nject:"default=zero"`
+	Count   int           `nject:"default=0x10"`
+	Ratio   float64       `nject:"default=1.5"`
+	On      bool          `nject:"default=true"`
+	Wait    time.Duration `nject:"default=3s,env=WAIT"`
+	Names   []string      `nject:"default=a;b;c"`
+	Port    uint16        `nject:"env=PORT"`
+	Missing s1            `nject:"env=MISSING"`
+}
+
+func TestFillerDefaults(t *testing.T) {
+	t.Parallel()
+	env := EnvLookup(func(name string) (string, bool) {
+		v, ok := map[string]string{"PORT": "8080"}[name]
+		return v, ok
+	})
+	var called bool
+	err := Run("TestFillerDefaults",
+		Required(s0("provided")),
+		Required(func() EnvLookup { return env }),
+		MustMakeStructBuilder(FillDefaults{}),
+		func(f FillDefaults) {
+			called = true
+			assert.Equal(t, s0("provided"), f.S0, "provided beats default")
+			assert.Equal(t, 16, f.Count)
+			assert.Equal(t, 1.5, f.Ratio) //nolint:testifylint // exact
+			assert.True(t, f.On)
+			assert.Equal(t, 3*time.Second, f.Wait)
+			assert.Equal(t, []string{"a", "b", "c"}, f.Names)
+			assert.Equal(t, uint16(8080), f.Port, "from env")
+			assert.Equal(t, s1(""), f.Missing, "not set")
+		},
+	)
+	require.NoError(t, err)
+	assert.True(t, called)
+
+	env = func(name string) (string, bool) {
+		return "bogus", name == "WAIT"
+	}
+	err = Run("TestFillerDefaults-bad-env",
+		Required(func() EnvLookup { return env }),
+		MustMakeStructBuilder(FillDefaults{}),
+		func(_ FillDefaults) {},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot set Wait from environment variable WAIT")
+}
+
+func TestFillerDefaultErrors(t *testing.T) {
+	t.Parallel()
+	_, err := MakeStructBuilder(struct {
+		I int `nject:"default=seven"`
+	}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid default for I")
+
+	_, err = MakeStructBuilder(struct {
+		M map[string]int `nject:"default=x"`
+	}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot parse map[string]int")
+
+	_, err = MakeStructBuilder(struct {
+		C chan int `nject:"env=C"`
+	}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot use env on C because chan int cannot be parsed")
+
+	_, err = MakeStructBuilder(struct {
+		Sub FillSubStruct `nject:"env=SUB"`
+	}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "because it is filled field-by-field")
+}
+
+type FillMethods struct {
+	S0 s0
+}
+
+func (f *FillMethods) Validate() s1 { return s1("valid " + f.S0) }
+
+func (f *FillMethods) Init() { f.S0 += "-init" }
+
+func TestFillerOptionalMethod(t *testing.T) {
+	t.Parallel()
+	var called bool
+	err := Run("TestFillerOptionalMethod",
+		s0("s0"),
+		MustMakeStructBuilder(&FillMethods{}, WithOptionalMethod("Init"), WithOptionalMethod("Validate"), WithOptionalMethod("Close")),
+		func(f *FillMethods, s s1, d *Debugging) {
+			called = true
+			assert.Equal(t, s0("s0-init"), f.S0, "Init changes the struct")
+			assert.Equal(t, s1("valid s0-init"), s)
+			assert.Contains(t, d.Trace, "WithOptionalMethod(Validate) on *nject.FillMethods: found")
+			assert.Contains(t, d.Trace, "WithOptionalMethod(Close) on *nject.FillMethods: not found, skipped")
+		},
+	)
+	require.NoError(t, err)
+	assert.True(t, called)
+
+	called = false
+	err = Run("TestFillerOptionalMethod-value",
+		s0("s0"),
+		MustMakeStructBuilder(FillMethods{}, WithOptionalMethod("Init")),
+		func(f FillMethods, d *Debugging) {
+			called = true
+			assert.Equal(t, s0("s0"), f.S0)
+			assert.Contains(t, d.Trace, "WithOptionalMethod(Init) on nject.FillMethods: only defined on the pointer receiver, skipped")
+		},
+	)
+	require.NoError(t, err)
+	assert.True(t, called)
+
+	_, err = MakeStructBuilder(FillMethods{}, WithMethodCall("Init"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the method has a pointer receiver so the struct must be built as a pointer")
+
+	_, err = MakeStructBuilder(&FillMethods{}, WithMethodCall("Close"))
+	assert.Error(t, err, "WithMethodCall requires the method")
+}
+
+type FillTree struct {
+	DB     *FillTreeDB    `nject:"build"`
+	Server FillTreeServer `nject:"build"`
+	Other  *FillTreeDB    `nject:"optional"`
+}
+
+type FillTreeDB struct {
+	Host s0
+	Pool *FillTreePool `nject:"build"`
+}
+
+type FillTreePool struct {
+	Size int `nject:"default=4"`
+	Name s1
+}
+
+type FillTreeServer struct {
+	Port s2
+}
+
+type FillTreeLoop struct {
+	Next *FillTreeLoop `nject:"build"`
+}
+
+func TestFillerBuild(t *testing.T) {
+	t.Parallel()
+	var called bool
+	err := Run("TestFillerBuild",
+		s0("host"),
+		s1("pool"),
+		s2("port"),
+		MustMakeStructBuilder(&FillTree{},
+			PostActionByName("Name", func(s *s1) { *s += "-checked" }, WithFill(true))),
+		func(f *FillTree) {
+			called = true
+			require.NotNil(t, f.DB)
+			require.NotNil(t, f.DB.Pool)
+			assert.Equal(t, s0("host"), f.DB.Host)
+			assert.Equal(t, 4, f.DB.Pool.Size)
+			assert.Equal(t, s1("pool-checked"), f.DB.Pool.Name, "post-action on nested field")
+			assert.Equal(t, s2("port"), f.Server.Port)
+			assert.Nil(t, f.Other, "not built")
+		},
+	)
+	require.NoError(t, err)
+	assert.True(t, called)
+
+	_, err = MakeStructBuilder(FillTreeLoop{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already being built")
+
+	_, err = MakeStructBuilder(struct {
+		I int `nject:"build"`
+	}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot use tag build")
+}
+
+func TestFillerPostActionError(t *testing.T) {
+	t.Parallel()
+	err := Run("TestFillerPostActionError",
+		s0("host"),
+		s1("pool"),
+		s2("port"),
+		MustMakeStructBuilder(&FillTree{},
+			PostActionByName("Name", func(s s1) TerminalError {
+				if s == "pool" {
+					return fmt.Errorf("bad name %s", s)
+				}
+				return nil
+			})),
+		func(f *FillTree) {
+			t.Fatal("should not be called")
+		},
+	)
+	require.Error(t, err)
+	assert.Equal(t, "DB.Pool.Name: bad name pool", err.Error())
+}
+
+type FillValidate struct {
+	Name    string        `nject:"default=x" validate:"required,min=1,max=5"`
+	Count   int           `nject:"default=3" validate:"min=1,max=10"`
+	Timeout time.Duration `nject:"default=2s" validate:"min=1s"`
+	Mode    string        `nject:"default=fast" validate:"oneof=fast slow"`
+	Tags    []string      `nject:"default=a;b" validate:"max=2"`
+}
+
+func TestFillerValidate(t *testing.T) {
+	t.Parallel()
+	var called bool
+	err := Run("TestFillerValidate",
+		MustMakeStructBuilder(FillValidate{}, WithValidateTag("validate")),
+		func(v FillValidate) {
+			called = true
+			assert.Equal(t, 3, v.Count)
+		},
+	)
+	require.NoError(t, err)
+	assert.True(t, called)
+
+	cases := []struct {
+		model any
+		want  string
+	}{
+		{
+			model: struct {
+				S string `nject:"optional" validate:"required"`
+			}{},
+			want: "S: is required",
+		},
+		{
+			model: struct {
+				S string `nject:"default=abcdef" validate:"max=5"`
+			}{},
+			want: "S: length must be at most 5",
+		},
+		{
+			model: struct {
+				I int `nject:"default=0" validate:"min=1"`
+			}{},
+			want: "I: must be at least 1",
+		},
+		{
+			model: struct {
+				U uint `nject:"default=11" validate:"max=10"`
+			}{},
+			want: "U: must be at most 10",
+		},
+		{
+			model: struct {
+				D time.Duration `nject:"default=500ms" validate:"min=1s"`
+			}{},
+			want: "D: must be at least 1s",
+		},
+		{
+			model: struct {
+				F float64 `nject:"default=0.5" validate:"oneof=1 2.5"`
+			}{},
+			want: "F: must be one of 1 2.5",
+		},
+	}
+	for _, tc := range cases {
+		err := Run("TestFillerValidate",
+			Required(MustMakeStructBuilder(tc.model, WithValidateTag("validate"))),
+			func() {},
+		)
+		if assert.Error(t, err, tc.want) {
+			assert.Equal(t, tc.want, err.Error())
+		}
+	}
+
+	// validation is off by default
+	err = Run("TestFillerValidate",
+		Required(MustMakeStructBuilder(struct {
+			S string `nject:"optional" validate:"required"`
+		}{})),
+		func() {},
+	)
+	assert.NoError(t, err)
+}
+
+func TestFillerValidateNested(t *testing.T) {
+	t.Parallel()
+	type pool struct {
+		Size int `nject:"default=0" validate:"min=1"`
+	}
+	type db struct {
+		Pool *pool `nject:"build"`
+	}
+	err := Run("TestFillerValidateNested",
+		Required(MustMakeStructBuilder(&struct {
+			DB db `nject:"build"`
+		}{}, WithValidateTag("validate"))),
+		func() {},
+	)
+	require.Error(t, err)
+	assert.Equal(t, "DB.Pool.Size: must be at least 1", err.Error())
+}
+
+func TestFillerValidateErrors(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		model any
+		want  string
+	}{
+		{
+			model: struct {
+				S string `validate:"bogus"`
+			}{},
+			want: "unknown rule 'bogus'",
+		},
+		{
+			model: struct {
+				S string `validate:"min=x"`
+			}{},
+			want: "invalid length 'x'",
+		},
+		{
+			model: struct {
+				I int `validate:"max=1.5"`
+			}{},
+			want: "invalid bound '1.5'",
+		},
+		{
+			model: struct {
+				B bool `validate:"min=1"`
+			}{},
+			want: "bounds cannot be used with bool",
+		},
+		{
+			model: struct {
+				S []string `validate:"oneof=a b"`
+			}{},
+			want: "oneof cannot be used with []string",
+		},
+		{
+			model: struct {
+				S string `validate:"oneof="`
+			}{},
+			want: "oneof needs at least one value",
+		},
+	}
+	for _, tc := range cases {
+		_, err := MakeStructBuilder(tc.model, WithValidateTag("validate"))
+		if assert.Error(t, err, tc.want) {
+			assert.Contains(t, err.Error(), tc.want)
+			assert.Contains(t, err.Error(), "invalid validate tag")
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package nject
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type validationRule func(v reflect.Value) error
+
+// makeValidator parses the rules of a validate tag and returns a
+// post-action function that checks them.
+func makeValidator(t reflect.Type, rules string) (func(any) TerminalError, error) {
+	var checks []validationRule
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		var check validationRule
+		var err error
+		switch name {
+		case "required":
+			check = func(v reflect.Value) error {
+				if v.IsZero() {
+					return fmt.Errorf("is required")
+				}
+				return nil
+			}
+		case "min":
+			check, err = boundRule(t, arg, "at least", func(c int) bool { return c >= 0 })
+		case "max":
+			check, err = boundRule(t, arg, "at most", func(c int) bool { return c <= 0 })
+		case "oneof":
+			check, err = oneOfRule(t, arg)
+		default:
+			err = fmt.Errorf("unknown rule '%s'", rule)
+		}
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return func(p any) TerminalError {
+		v := reflect.ValueOf(p).Elem()
+		for _, check := range checks {
+			if err := check(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+// boundRule checks a length or a value against a bound.  ok is
+// given the comparison of the value to the bound.
+func boundRule(t reflect.Type, arg string, describe string, ok func(int) bool) (validationRule, error) {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid length '%s': %w", arg, err)
+		}
+		return func(v reflect.Value) error {
+			if !ok(compareOrdered(int64(v.Len()), int64(n))) {
+				return fmt.Errorf("length must be %s %d", describe, n)
+			}
+			return nil
+		}, nil
+	}
+	bound, err := parseFieldValue(t, arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bound '%s': %w", arg, err)
+	}
+	var compare func(a, b reflect.Value) int
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		compare = func(a, b reflect.Value) int { return compareOrdered(a.Int(), b.Int()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		compare = func(a, b reflect.Value) int { return compareOrdered(a.Uint(), b.Uint()) }
+	case reflect.Float32, reflect.Float64:
+		compare = func(a, b reflect.Value) int { return compareOrdered(a.Float(), b.Float()) }
+	default:
+		return nil, fmt.Errorf("bounds cannot be used with %s", t)
+	}
+	return func(v reflect.Value) error {
+		if !ok(compare(v, bound)) {
+			return fmt.Errorf("must be %s %s", describe, arg)
+		}
+		return nil
+	}, nil
+}
+
+func oneOfRule(t reflect.Type, arg string) (validationRule, error) {
+	if !t.Comparable() || t.Kind() == reflect.Slice {
+		return nil, fmt.Errorf("oneof cannot be used with %s", t)
+	}
+	var allowed []any
+	for _, s := range strings.Fields(arg) {
+		v, err := parseFieldValue(t, s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s': %w", s, err)
+		}
+		allowed = append(allowed, v.Interface())
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("oneof needs at least one value")
+	}
+	return func(v reflect.Value) error {
+		for _, a := range allowed {
+			if v.Interface() == a {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", arg)
+	}, nil
+}
+
+func compareOrdered[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -67,6 +67,8 @@ func effectiveOutputs(fn reflectType) ([]reflect.Type, []reflect.Type) {
 
 func (c Collection) netFlows(f func(fm *provider) ([]reflect.Type, []reflect.Type)) ([]reflect.Type, []reflect.Type) {
 	available := make(interfaceMap)
+	// errors in the rules will be reported by Bind
+	rules, _ := newMatchRules(&c)
 	seenIn := make(map[reflect.Type]struct{})
 	uniqueIn := make([]reflect.Type, 0, len(c.contents)*4)
 	seenOut := make(map[reflect.Type]struct{})
@@ -75,7 +77,7 @@ func (c Collection) netFlows(f func(fm *provider) ([]reflect.Type, []reflect.Typ
 		inputs, outputs := f(fm)
 		inputsByType := make(map[reflect.Type]struct{})
 		for _, input := range inputs {
-			t, _, err := available.bestMatch(getTypeCode(input), "input")
+			t, _, err := available.bestMatch(getTypeCode(input), "input", rules)
 			if err == nil {
 				input = t.Type()
 			}
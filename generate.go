@@ -69,16 +69,16 @@ func generateParameterMap(
 }
 
 // generateInputMapper returns a function that copies values from valueCollection to an array of reflect.Value
-func generateInputMapper(fm *provider, start int, param flowType, rmap map[typeCode]typeCode, vmap map[typeCode]int, purpose string) (func(valueCollection) []reflect.Value, error) {
+func generateInputMapper(fm *provider, start int, param flowType, rmap map[typeCode]typeCode, vmap map[typeCode]int, purpose string, tr *tracer) (func(valueCollection) []reflect.Value, error) {
 	pMap, err := generateParameterMap(fm, param, start, rmap, vmap, purpose+" valueCollection->[]")
 	if err != nil {
 		return nil, err
 	}
 
 	return func(v valueCollection) []reflect.Value {
-		if debugEnabled() {
-			debugf("%s: %s [%s] numIn:%d, m:%v", fm, param, formatFlow(fm.flows[param]), pMap.len, pMap.vcIndex)
-			dumpValueArray(v, "", vmap)
+		if tr.enabled() {
+			tr.debugf("%s: %s [%s] numIn:%d, m:%v", fm, param, tr.formatFlow(fm.flows[param]), pMap.len, pMap.vcIndex)
+			tr.dumpValueArray(v, "", vmap)
 		}
 		in := make([]reflect.Value, pMap.len)
 		for i := start; i < pMap.len; i++ {
@@ -157,12 +157,14 @@ func generateWrappers(
 	fm *provider,
 	downVmap map[typeCode]int, // value collection map for variables passed down
 	upVmap map[typeCode]int, // value collection map for return values coming up
+	tr *tracer, // debug trace for this bind
 ) error {
+	tr.decision(fm, "generate", fm.class)
 	fv := getCanCall(fm.fn)
 
 	switch fm.class {
 	case finalFunc:
-		inMap, err := generateInputMapper(fm, 0, inputParams, fm.downRmap, downVmap, "in(final)")
+		inMap, err := generateInputMapper(fm, 0, inputParams, fm.downRmap, downVmap, "in(final)", tr)
 		if err != nil {
 			return err
 		}
@@ -176,7 +178,7 @@ func generateWrappers(
 		}
 
 	case wrapperFunc:
-		inMap, err := generateInputMapper(fm, 1, inputParams, fm.downRmap, downVmap, "in(w)") // parameters to the middleware handler
+		inMap, err := generateInputMapper(fm, 1, inputParams, fm.downRmap, downVmap, "in(w)", tr) // parameters to the middleware handler
 		if err != nil {
 			return err
 		}
@@ -188,7 +190,7 @@ func generateWrappers(
 		if err != nil {
 			return err
 		}
-		retMap, err := generateInputMapper(fm, 0, receivedParams, fm.upRmap, upVmap, "ret(w)") // return values from inner()
+		retMap, err := generateInputMapper(fm, 0, receivedParams, fm.upRmap, upVmap, "ret(w)", tr) // return values from inner()
 		if err != nil {
 			return err
 		}
@@ -249,7 +251,7 @@ func generateWrappers(
 		}
 
 	case fallibleInjectorFunc:
-		inMap, err := generateInputMapper(fm, 0, inputParams, fm.downRmap, downVmap, "in(fallible)")
+		inMap, err := generateInputMapper(fm, 0, inputParams, fm.downRmap, downVmap, "in(fallible)", tr)
 		if err != nil {
 			return err
 		}
@@ -268,7 +270,7 @@ func generateWrappers(
 		upVerrorIndex := upVmap[getTypeCode(errorType)]
 		var memoized cacherFunc
 		if fm.memoized {
-			memoized = generateCache(fm.id, fv, len(fm.flows[inputParams]), fm.mapKeyCheck)
+			memoized = generateCache(fm.id, fv, len(fm.flows[inputParams]), fm.mapKeyCheck, tr)
 		}
 		fm.wrapFallibleInjector = func(v valueCollection) bool {
 			in := inMap(v)
@@ -281,19 +283,19 @@ func generateWrappers(
 			if out[errorIndex].Interface() != nil {
 				zero(v)
 				v[upVerrorIndex] = out[errorIndex].Convert(errorType)
-				if debugEnabled() {
-					debugln("ABOUT TO RETURN ERROR")
-					dumpValueArray(v, "error return", upVmap)
+				if tr.enabled() {
+					tr.debugln("ABOUT TO RETURN ERROR")
+					tr.dumpValueArray(v, "error return", upVmap)
 				}
 				return true
 			}
 			outMap(v, append(out[:errorIndex], out[errorIndex+1:]...))
-			debugln("ABOUT TO RETURN NIL")
+			tr.debugln("ABOUT TO RETURN NIL")
 			return false
 		}
 
 	case injectorFunc:
-		inMap, err := generateInputMapper(fm, 0, inputParams, fm.downRmap, downVmap, "in")
+		inMap, err := generateInputMapper(fm, 0, inputParams, fm.downRmap, downVmap, "in", tr)
 		if err != nil {
 			return err
 		}
@@ -302,7 +304,7 @@ func generateWrappers(
 			return err
 		}
 		if fm.memoized {
-			memoized := generateCache(fm.id, fv, len(fm.flows[inputParams]), fm.mapKeyCheck)
+			memoized := generateCache(fm.id, fv, len(fm.flows[inputParams]), fm.mapKeyCheck, tr)
 			fm.wrapFallibleInjector = func(v valueCollection) bool {
 				in := inMap(v)
 				outMap(v, memoized(in))
@@ -317,7 +319,7 @@ func generateWrappers(
 		}
 
 	case staticInjectorFunc:
-		inMap, err := generateInputMapper(fm, 0, inputParams, fm.downRmap, downVmap, "in")
+		inMap, err := generateInputMapper(fm, 0, inputParams, fm.downRmap, downVmap, "in", tr)
 		if err != nil {
 			return err
 		}
@@ -325,7 +327,7 @@ func generateWrappers(
 		if err != nil {
 			return err
 		}
-		lookup := generateLookup(fm, fv, len(fm.flows[inputParams]))
+		lookup := generateLookup(fm, fv, len(fm.flows[inputParams]), tr)
 		fm.wrapStaticInjector = func(v valueCollection) error {
 			in := inMap(v)
 			var out []reflect.Value
@@ -339,7 +341,7 @@ func generateWrappers(
 		}
 
 	case fallibleStaticInjectorFunc:
-		inMap, err := generateInputMapper(fm, 0, inputParams, fm.downRmap, downVmap, "in")
+		inMap, err := generateInputMapper(fm, 0, inputParams, fm.downRmap, downVmap, "in", tr)
 		if err != nil {
 			return err
 		}
@@ -355,9 +357,9 @@ func generateWrappers(
 		if err != nil {
 			return err
 		}
-		lookup := generateLookup(fm, fv, len(fm.flows[inputParams]))
+		lookup := generateLookup(fm, fv, len(fm.flows[inputParams]), tr)
 		fm.wrapStaticInjector = func(v valueCollection) error {
-			debugf("RUNNING %s", fm)
+			tr.debugf("RUNNING %s", fm)
 			in := inMap(v)
 			var out []reflect.Value
 			if lookup != nil {
@@ -369,20 +371,20 @@ func generateWrappers(
 			out[errorIndex] = out[errorIndex].Convert(errorType)
 			outMap(v, out)
 			if err != nil {
-				if debugEnabled() {
-					debugf("Zeroing for %s", fm)
-					dumpValueArray(v, "BEFORE", downVmap)
+				if tr.enabled() {
+					tr.debugf("Zeroing for %s", fm)
+					tr.dumpValueArray(v, "BEFORE", downVmap)
 				}
 				zero(v)
-				if debugEnabled() {
-					dumpValueArray(v, "AFTER", downVmap)
-					debugf("RETURNING %v", err)
+				if tr.enabled() {
+					tr.dumpValueArray(v, "AFTER", downVmap)
+					tr.debugf("RETURNING %v", err)
 				}
 				return err.(error)
 			}
-			if debugEnabled() {
-				debugf("NOT zeroing for %s", fm)
-				debugf("RETURNING nil")
+			if tr.enabled() {
+				tr.debugf("NOT zeroing for %s", fm)
+				tr.debugf("RETURNING nil")
 			}
 			return nil
 		}
@@ -0,0 +1,369 @@
+package nject
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// In can be embedded in a struct to mark it as a parameter struct.
+// When a provider takes a struct that embeds In as a parameter, each
+// of the exported fields of the struct is received separately, as if
+// each field was a parameter of the function.
+//
+//	type HandlerDeps struct {
+//		nject.In
+//		DB     *sql.DB
+//		Logger *slog.Logger
+//		Cache  *redis.Client `nject:"optional"`
+//	}
+//
+//	func Handler(deps HandlerDeps, w http.ResponseWriter) { ... }
+//
+// The "nject" struct tag controls how the fields are treated.  Options
+// are separated by commas:
+//
+// "-" & "skip": the field is not filled and is left as the zero value.
+//
+// "optional": if nothing earlier in the chain provides the exact type
+// of the field, then the field is left as the zero value instead of
+// making the chain invalid.  Optional fields are only filled by
+// providers that are included in the chain for other reasons: they
+// never cause a provider to be included.
+//
+// "name=something": accepted for symmetry with Out.
+type In struct{}
+
+var inType = reflect.TypeOf(In{})
+
+// inStructs records how the parameter structs of a function were
+// replaced by their fields so that the function can be rebuilt without
+// the optional fields that have no provider.
+type inStructs struct {
+	orig   reflect.Value
+	params []inParam
+}
+
+// inParam is one parameter of the original function
+type inParam struct {
+	typ    reflect.Type
+	fields []spreadField // nil unless typ embeds In
+}
+
+// gatherIn is called on all new providers to replace parameter
+// structs that embed In with their fields.
+func (fm *provider) gatherIn() {
+	switch fm.fn.(type) {
+	case Reflective, generatedFromInjectionChain:
+		return
+	}
+	v := reflect.ValueOf(fm.fn)
+	if !v.IsValid() || v.Type().Kind() != reflect.Func {
+		return
+	}
+	ft := v.Type()
+	ins := &inStructs{orig: v}
+	var found bool
+	for _, in := range typesIn(ft) {
+		param := inParam{typ: in}
+		if in.Kind() == reflect.Struct {
+			if f, ok := in.FieldByName(inType.Name()); ok && f.Anonymous && f.Type == inType {
+				fields, err := structFields(in)
+				if err != nil {
+					fm.fatal = fmt.Errorf("nject.In: %w", err)
+					return
+				}
+				for _, field := range fields {
+					if field.mustConsume {
+						fm.fatal = fmt.Errorf("nject.In: mustconsume cannot be used on %s of %s", field.name, in)
+						return
+					}
+				}
+				param.fields = fields
+				found = true
+			}
+		}
+		ins.params = append(ins.params, param)
+	}
+	if !found {
+		return
+	}
+	if ft.IsVariadic() {
+		fm.fatal = fmt.Errorf("nject.In: cannot be used with variadic function %s", ft)
+		return
+	}
+	fm.inStructs = ins
+	fm.fn = ins.build(nil)
+}
+
+// baseFn is the function before parameter structs were replaced by
+// their fields.  Annotations that rewrite the function start from it.
+func (fm *provider) baseFn() any {
+	if fm.inStructs != nil {
+		return fm.inStructs.orig.Interface()
+	}
+	return fm.fn
+}
+
+// replaceFn replaces the function with a rewritten version of baseFn.
+// The provider gets a new id since the Memoize cache is keyed by id
+// and the rewritten function must not share the original's cache.
+func (fm *provider) replaceFn(fn any) {
+	fm.fn = fn
+	fm.id = atomic.AddInt32(&idCounter, 1)
+	if fm.inStructs != nil {
+		fm.inStructs = nil
+		fm.gatherIn()
+	}
+}
+
+func (ins *inStructs) optionalTypes() map[reflect.Type]string {
+	optional := make(map[reflect.Type]string)
+	for _, param := range ins.params {
+		for _, field := range param.fields {
+			if field.optional {
+				optional[field.typ] = field.name
+			}
+		}
+	}
+	return optional
+}
+
+func (ins *inStructs) withoutOptional(missing map[reflect.Type]struct{}) any {
+	return ins.build(missing)
+}
+
+// build creates a function that receives the fields of the parameter
+// structs instead of the structs.  Optional fields whose types are in
+// missing are not received.
+func (ins *inStructs) build(missing map[reflect.Type]struct{}) any {
+	ft := ins.orig.Type()
+	var inputs []reflect.Type
+	for _, param := range ins.params {
+		if param.fields == nil {
+			inputs = append(inputs, param.typ)
+			continue
+		}
+		for _, field := range param.fields {
+			if _, ok := missing[field.typ]; ok && field.optional {
+				continue
+			}
+			inputs = append(inputs, field.typ)
+		}
+	}
+	return reflect.MakeFunc(reflect.FuncOf(inputs, typesOut(ft), false),
+		func(args []reflect.Value) []reflect.Value {
+			origArgs := make([]reflect.Value, len(ins.params))
+			for i, param := range ins.params {
+				if param.fields == nil {
+					origArgs[i] = args[0]
+					args = args[1:]
+					continue
+				}
+				s := reflect.New(param.typ).Elem()
+				for _, field := range param.fields {
+					if _, ok := missing[field.typ]; ok && field.optional {
+						continue
+					}
+					s.FieldByIndex(field.index).Set(args[0])
+					args = args[1:]
+				}
+				origArgs[i] = s
+			}
+			return ins.orig.Call(origArgs)
+		}).Interface()
+}
+
+// optionalInputs is implemented by things that can rebuild their
+// function so that some of its optional inputs are not received
+type optionalInputs interface {
+	// optionalTypes maps the optional input types to the names of
+	// the fields they fill
+	optionalTypes() map[reflect.Type]string
+	withoutOptional(missing map[reflect.Type]struct{}) any
+}
+
+var (
+	_ optionalInputs = &inStructs{}
+	_ optionalInputs = &filler{}
+)
+
+// optionalInputs returns nil unless the provider has optional
+// inputs that have not yet been resolved.
+func (fm *provider) optionalInputs() optionalInputs {
+	if fm.optionalMissing != nil || fm.fatal != nil {
+		return nil
+	}
+	var o optionalInputs
+	if fm.inStructs != nil {
+		o = fm.inStructs
+	} else if fo, ok := fm.fn.(optionalInputs); ok {
+		o = fo
+	} else {
+		return nil
+	}
+	if len(o.optionalTypes()) == 0 {
+		return nil
+	}
+	return o
+}
+
+// withoutOptional returns a copy of the provider with its function
+// rebuilt so that it does not receive the missing optional types.
+// Like replaceFn, the copy gets a different id so that it does not
+// share a Memoize cache with rebuilds that receive other inputs.
+func (fm *provider) withoutOptional(o optionalInputs, missing map[reflect.Type]struct{}) *provider {
+	nfm := fm.copy()
+	nfm.fn = o.withoutOptional(missing)
+	nfm.id = fm.optionalID(missing)
+	nfm.optionalMissing = missing
+	return nfm
+}
+
+type optionalKey struct {
+	id      int32
+	missing string
+}
+
+// optionalID returns the id for the provider rebuilt without the
+// missing types.  The same rebuild always gets the same id so that
+// Memoize works across binds.  The ids are kept in a map that is
+// shared by the copies of the provider so they are freed along with it.
+func (fm *provider) optionalID(missing map[reflect.Type]struct{}) int32 {
+	if fm.optionalIDs == nil {
+		return atomic.AddInt32(&idCounter, 1)
+	}
+	codes := make([]int, 0, len(missing))
+	for t := range missing {
+		codes = append(codes, int(getTypeCode(t)))
+	}
+	sort.Ints(codes)
+	key := optionalKey{id: fm.id, missing: fmt.Sprint(codes)}
+	if newID, ok := fm.optionalIDs.Load(key); ok {
+		return newID.(int32)
+	}
+	newID, _ := fm.optionalIDs.LoadOrStore(key, atomic.AddInt32(&idCounter, 1))
+	return newID.(int32)
+}
+
+// resolveOptionalInputs decides which optional inputs will be received.
+// Optional inputs must not cause providers to be included that would
+// not otherwise be included, so first the collection is bound (without
+// binding for real) with all of the optional inputs removed.  Then the
+// optional inputs that are provided by included providers earlier in
+// the chain are put back.  When nothing could provide any of the optional types,
+// the probe is skipped.
+func resolveOptionalInputs(sc *Collection, invokeF *provider, initF *provider, tr *tracer) *Collection {
+	wanted := make(map[typeCode]struct{})
+	for _, fm := range sc.contents {
+		if o := fm.optionalInputs(); o != nil {
+			for t := range o.optionalTypes() {
+				wanted[getTypeCode(t)] = struct{}{}
+			}
+		}
+	}
+	if len(wanted) == 0 {
+		return sc
+	}
+	allMissing := func(o optionalInputs) map[reflect.Type]struct{} {
+		missing := make(map[reflect.Type]struct{})
+		for t := range o.optionalTypes() {
+			missing[t] = struct{}{}
+		}
+		return missing
+	}
+
+	probe := &Collection{
+		name:     sc.name,
+		contents: make([]*provider, len(sc.contents)),
+	}
+	needProbe := initInvokeProvides(invokeF, wanted) || initInvokeProvides(initF, wanted)
+	for i, fm := range sc.contents {
+		if o := fm.optionalInputs(); o != nil {
+			pfm := fm.withoutOptional(o, allMissing(o))
+			pfm.probeOf = fm
+			fm = pfm
+		} else if fm.conversion != nil {
+			needProbe = true
+		} else {
+			for tc := range providerOutputs(fm) {
+				if _, ok := wanted[tc]; ok {
+					needProbe = true
+					break
+				}
+			}
+		}
+		probe.contents[i] = fm
+	}
+	availableFor := make(map[*provider]map[reflect.Type]struct{})
+	if needProbe {
+		// errors will be reported by the real bind
+		_ = doBind(probe, invokeF, initF, false, nil, func(funcs []*provider) {
+			available := make(map[typeCode]struct{})
+			for _, fm := range funcs {
+				if fm.probeOf != nil {
+					found := make(map[reflect.Type]struct{})
+					for t := range fm.optionalMissing {
+						if _, ok := available[getTypeCode(t)]; ok {
+							found[t] = struct{}{}
+						}
+					}
+					availableFor[fm.probeOf] = found
+				}
+				if fm.include {
+					for _, tc := range fm.flows[outputParams] {
+						available[tc] = struct{}{}
+					}
+				}
+			}
+		})
+	}
+
+	resolved := &Collection{
+		name:     sc.name,
+		contents: make([]*provider, len(sc.contents)),
+	}
+	for i, fm := range sc.contents {
+		o := fm.optionalInputs()
+		if o == nil {
+			resolved.contents[i] = fm
+			continue
+		}
+		names := o.optionalTypes()
+		missing := allMissing(o)
+		for t := range availableFor[fm] {
+			delete(missing, t)
+		}
+		nfm := fm.withoutOptional(o, missing)
+		if len(missing) > 0 {
+			list := make([]string, 0, len(missing))
+			for t := range missing {
+				list = append(list, names[t])
+			}
+			sort.Strings(list)
+			tr.decision(nfm, "optional input", fmt.Sprintf("not provided: %s", strings.Join(list, ", ")))
+		}
+		resolved.contents[i] = nfm
+	}
+	return resolved
+}
+
+// initInvokeProvides reports if the init or invoke function
+// provides any of the wanted types
+func initInvokeProvides(fm *provider, wanted map[typeCode]struct{}) bool {
+	if fm == nil {
+		return false
+	}
+	cfm, err := characterizeInitInvoke(fm, charContext{inputsAreStatic: true})
+	if err != nil {
+		return false
+	}
+	for _, tc := range cfm.flows[outputParams] {
+		if _, ok := wanted[tc]; ok {
+			return true
+		}
+	}
+	return false
+}
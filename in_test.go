@@ -0,0 +1,128 @@
+package nject
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type inParams struct {
+	In
+	S0      s0
+	S1      s1 `nject:"optional"`
+	S2      s2 `nject:"-"`
+	private s3
+}
+
+func TestIn(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got inParams
+		require.NoError(t, Run(t.Name(),
+			s0("zero"),
+			Required(s1("one")),
+			s2("two"),
+			func(p inParams) { got = p },
+		))
+		assert.Equal(t, s0("zero"), got.S0)
+		assert.Equal(t, s1("one"), got.S1)
+		assert.Equal(t, s2(""), got.S2, "skipped")
+	})
+}
+
+func TestInOptional(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got inParams
+		var called bool
+		require.NoError(t, Run(t.Name(),
+			s0("zero"),
+			func(p inParams, d *Debugging) {
+				called = true
+				got = p
+				assert.Contains(t, d.Trace, "optional input")
+			},
+		))
+		assert.True(t, called)
+		assert.Equal(t, s0("zero"), got.S0)
+		assert.Equal(t, s1(""), got.S1)
+
+		var providerCalled bool
+		require.NoError(t, Run(t.Name(),
+			s0("zero"),
+			func() s1 {
+				providerCalled = true
+				return "one"
+			},
+			func(p inParams) { got = p },
+		))
+		assert.False(t, providerCalled, "optional fields do not include providers that are not otherwise used")
+		assert.Equal(t, s1(""), got.S1)
+
+		err := Run(t.Name(),
+			s1("one"),
+			func(_ inParams) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no match for its input parameter nject/v2.s0")
+	})
+}
+
+func TestInOptionalMemoized(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		p := Memoize(func(p inParams) s4 { return s4(string(p.S0) + string(p.S1)) })
+		var got s4
+		require.NoError(t, Run(t.Name(),
+			s0("zero"),
+			Required(s1("one")),
+			p,
+			func(s s4) { got = s },
+		))
+		assert.Equal(t, s4("zeroone"), got)
+		require.NoError(t, Run(t.Name(),
+			s0("zero"),
+			p,
+			func(s s4) { got = s },
+		))
+		assert.Equal(t, s4("zero"), got)
+	})
+}
+
+func TestInFlows(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		p := Provide("in", func(i int, p inParams) s4 { return s4(fmt.Sprint(i, p.S0)) })
+		inputs, outputs := p.DownFlows()
+		assert.Equal(t, "[int nject.s0 nject.s1]", fmt.Sprint(inputs))
+		assert.Equal(t, "[nject.s4]", fmt.Sprint(outputs))
+
+		var got s4
+		require.NoError(t, Run(t.Name(),
+			7,
+			s0("zero"),
+			As[fmt.Stringer](func(inner func(asImpl), _ inParams) { inner("five") }),
+			p,
+			func(s s4, _ fmt.Stringer) { got = s },
+		))
+		assert.Equal(t, s4("7zero"), got)
+	})
+}
+
+func TestInBadTag(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		type params struct {
+			In
+			S0 s0 `nject:"bogus"`
+		}
+		err := Run(t.Name(), func(_ params) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "nject.In: unknown tag value 'bogus' on field S0")
+
+		type mustParams struct {
+			In
+			S0 s0 `nject:"mustconsume"`
+		}
+		err = Run(t.Name(), s0("x"), func(_ mustParams) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "nject.In: mustconsume cannot be used on field S0")
+	})
+}
@@ -1,7 +1,9 @@
 package nject
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 )
 
 type includeWorkingData struct {
@@ -15,6 +17,7 @@ type includeWorkingData struct {
 	clusterMembers  []*provider
 	wantedInCluster bool
 	hasFlow         [lastFlowType]func(typeCode) bool // populated and used in reorder
+	reorderMoved    bool                              // set in reorder
 }
 
 //
@@ -65,18 +68,133 @@ type includeWorkingData struct {
 //	fm.wanted
 //
 
-func computeDependenciesAndInclusion(funcs []*provider, initF *provider) ([]*provider, error) {
-	var err error
-	funcs, err = reorder(funcs, initF)
-	if err != nil {
-		return nil, err
+// When there are providers marked Reorder, reordering and inclusion
+// are repeated until the set of excluded providers stops changing.
+// Each reordering ignores the providers that were excluded by the
+// previous inclusion pass.
+//
+// Inclusion is also repeated when a Default is included along with
+// another provider of its type.  The Default is then excluded.
+func computeDependenciesAndInclusion(funcs []*provider, initF *provider, rules *matchRules, tr *tracer) ([]*provider, error) {
+	var excluded map[*provider]bool
+	yielded := make(map[*provider]error)
+	for pass := 1; ; pass++ {
+		if pass > 1 {
+			for _, fm := range funcs {
+				fm.resetInclusion()
+			}
+		}
+		ordered, err := reorder(funcs, initF, excluded, rules, tr.inPhase(phaseReorder))
+		if err != nil {
+			return nil, err
+		}
+		ordered, err = computeInclusion(ordered, initF, rules, yielded, tr)
+		if err != nil {
+			return nil, err
+		}
+		nowExcluded := make(map[*provider]bool)
+		for _, fm := range ordered {
+			if !fm.include {
+				nowExcluded[fm] = true
+			}
+		}
+		if defaultsYield(ordered, yielded, tr) {
+			excluded = nowExcluded
+			continue
+		}
+		if !hasReorder(funcs) {
+			return ordered, checkStrict(ordered)
+		}
+		switch {
+		case sameProviderSet(excluded, nowExcluded):
+			tr.debugf("reorder reached a fixed point after %d passes", pass)
+			return ordered, checkStrict(ordered)
+		case pass >= maxReorderPasses:
+			tr.debugf("reorder did not reach a fixed point after %d passes", pass)
+			return nil, fmt.Errorf("reordering did not reach a fixed point after %d passes, the providers marked Reorder keep changing which providers are excluded", pass)
+		}
+		excluded = nowExcluded
+	}
+}
+
+// defaultsYield finds included Defaults that have another included
+// provider of one of their types and adds them to yielded.  It
+// returns true if it found any.
+func defaultsYield(funcs []*provider, yielded map[*provider]error, tr *tracer) bool {
+	var found bool
+	var providers map[typeCode][]*provider
+	for _, fm := range funcs {
+		if !fm.isDefault || !fm.include {
+			continue
+		}
+		if providers == nil {
+			providers = providersByType(funcs)
+		}
+		for _, tc := range fm.flows[outputParams] {
+			for _, other := range providers[tc] {
+				if other == fm || other.isDefault {
+					continue
+				}
+				yielded[fm] = fmt.Errorf("Default yields to %s which provides %s", other, tc)
+				tr.decision(fm, "default yields", yielded[fm])
+				found = true
+				break
+			}
+			if yielded[fm] != nil {
+				break
+			}
+		}
 	}
+	return found
+}
+
+const maxReorderPasses = 10
+
+func hasReorder(funcs []*provider) bool {
+	for _, fm := range funcs {
+		if fm.reorder {
+			return true
+		}
+	}
+	return false
+}
+
+func sameProviderSet(a, b map[*provider]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for fm := range a {
+		if !b[fm] {
+			return false
+		}
+	}
+	return true
+}
+
+// resetInclusion clears what reorder and computeInclusion set so that
+// they can be run again.
+func (fm *provider) resetInclusion() {
+	fm.d = includeWorkingData{}
+	fm.cannotInclude = nil
+	fm.wanted = false
+	fm.whyIncluded = ""
+	fm.include = fm.required
+	fm.upRmap = make(map[typeCode]typeCode)
+	fm.downRmap = make(map[typeCode]typeCode)
+	fm.bypassRmap = nil
+}
+
+func computeInclusion(funcs []*provider, initF *provider, rules *matchRules, yielded map[*provider]error, tr *tracer) ([]*provider, error) {
+	var err error
 	for i, fm := range funcs {
 		fm.chainPosition = i
 	}
-	debugln("initial set of functions")
+	tr.debugln("initial set of functions")
 	for _, fm := range funcs {
-		debugf("\t%s", fm)
+		tr.debugf("\t%s", fm)
+		if err := yielded[fm]; err != nil {
+			fm.d.excluded = err
+		}
 		if fm.mustConsume != nil {
 			fm.d.mustConsumeFlow[outputParams] = true
 		}
@@ -95,22 +213,22 @@ func computeDependenciesAndInclusion(funcs []*provider, initF *provider) ([]*pro
 			fm.wanted = true
 		}
 	}
-	debugln("calculate flows, initial")
-	err = providesReturns(funcs, initF)
+	tr.debugln("calculate flows, initial")
+	err = providesReturns(funcs, initF, rules, tr)
 	if err != nil {
 		return nil, err
 	}
 
-	debugln("check chain validity, no provider excluded")
-	err = validateChainMarkIncludeExclude(funcs, true)
+	tr.debugln("check chain validity, no provider excluded")
+	err = validateChainMarkIncludeExclude(funcs, true, tr)
 	if err != nil {
 		return nil, err
 	}
 
-	debugln("eliminating providers that cannot be included")
+	tr.debugln("eliminating providers that cannot be included")
 	for _, fm := range funcs {
 		if fm.cannotInclude != nil {
-			debugf("Excluding %s: %s", fm, fm.cannotInclude)
+			tr.debugf("Excluding %s: %s", fm, fm.cannotInclude)
 			fm.d.excluded = fm.cannotInclude
 			fm.include = false
 		}
@@ -136,8 +254,8 @@ func computeDependenciesAndInclusion(funcs []*provider, initF *provider) ([]*pro
 		}
 	}
 
-	debugln("eliminate unused providers")
-	eliminateUnused(funcs)
+	tr.debugln("eliminate unused providers")
+	eliminateUnused(funcs, tr)
 
 	tryWithout := func(without ...*provider) {
 		if len(without) == 1 {
@@ -146,9 +264,9 @@ func computeDependenciesAndInclusion(funcs []*provider, initF *provider) ([]*pro
 				// wanted functions from clusters
 				return
 			}
-			debugf("check chain validity, excluding %s", without[0])
+			tr.debugf("check chain validity, excluding %s", without[0])
 		} else {
-			debugf("check chain validity, excluding %d in cluster %s", len(without), without[0])
+			tr.debugf("check chain validity, excluding %d in cluster %s", len(without), without[0])
 		}
 		for _, fm := range without {
 			fm.d.excluded = fmt.Errorf("excluded to see what happens")
@@ -158,13 +276,13 @@ func computeDependenciesAndInclusion(funcs []*provider, initF *provider) ([]*pro
 				}
 			}
 		}
-		debugf("length of without before: %d", len(without))
+		tr.debugf("length of without before: %d", len(without))
 		//nolint:govet // err shadow
-		err := validateChainMarkIncludeExclude(funcs, false)
-		debugf("length of without after: %d", len(without))
+		err := validateChainMarkIncludeExclude(funcs, false, tr)
+		tr.debugf("length of without after: %d", len(without))
 		for _, fm := range without {
 			if err == nil {
-				fm.d.excluded = fmt.Errorf("not required, not desired, not necessary")
+				fm.d.excluded = errNotNecessary
 			} else {
 				fm.whyIncluded = fmt.Sprintf("if excluded then: %s", err)
 				fm.d.excluded = nil
@@ -177,8 +295,8 @@ func computeDependenciesAndInclusion(funcs []*provider, initF *provider) ([]*pro
 		}
 	}
 
-	debugln("attempt to eliminate additional providers")
-	for _, fm := range proposeEliminations(funcs) {
+	tr.debugln("attempt to eliminate additional providers")
+	for _, fm := range proposeEliminations(funcs, tr) {
 		if fm.d.excluded != nil {
 			continue
 		}
@@ -191,26 +309,26 @@ func computeDependenciesAndInclusion(funcs []*provider, initF *provider) ([]*pro
 		}
 	}
 
-	debugln("final set of functions")
+	tr.debugln("final set of functions")
 	for _, fm := range funcs {
 		if fm.d.excluded == nil {
 			fm.cannotInclude = nil
-			debugf("\tinclude %s --- %s", fm, fm.whyIncluded)
+			tr.decision(fm, "include", fm.whyIncluded)
 		} else {
 			if fm.cannotInclude == nil {
 				fm.cannotInclude = fm.d.excluded
 			}
-			debugf("\texclude %s --- %s", fm, fm.cannotInclude)
+			tr.decision(fm, "exclude", fm.cannotInclude)
 		}
 	}
 
-	debugln("final calculate flows")
-	err = providesReturns(funcs, initF)
+	tr.debugln("final calculate flows")
+	err = providesReturns(funcs, initF, rules, tr)
 	if err != nil {
 		return nil, fmt.Errorf("internal error: uh oh: %w", err)
 	}
-	debugf("final check chain validity")
-	err = validateChainMarkIncludeExclude(funcs, true)
+	tr.debugf("final check chain validity")
+	err = validateChainMarkIncludeExclude(funcs, true, tr)
 	if err != nil {
 		return nil, fmt.Errorf("internal error: uh oh #2: %w", err)
 	}
@@ -218,7 +336,26 @@ func computeDependenciesAndInclusion(funcs []*provider, initF *provider) ([]*pro
 	return funcs, nil
 }
 
-func validateChainMarkIncludeExclude(funcs []*provider, canRemoveDesired bool) error {
+// checkStrict returns an error listing the providers marked Strict
+// that were excluded.
+func checkStrict(funcs []*provider) error {
+	var excluded []string
+	for _, fm := range funcs {
+		if fm.strict && !fm.include {
+			excluded = append(excluded, fmt.Sprintf("%s: %s", fm, fm.cannotInclude))
+		}
+	}
+	switch len(excluded) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("strict provider was excluded: %s", excluded[0])
+	default:
+		return fmt.Errorf("%d strict providers were excluded:\n\t%s", len(excluded), strings.Join(excluded, "\n\t"))
+	}
+}
+
+func validateChainMarkIncludeExclude(funcs []*provider, canRemoveDesired bool, tr *tracer) error {
 	remainingFuncs := make([]*provider, 0, len(funcs))
 	for _, fm := range funcs {
 		if fm.d.excluded == nil {
@@ -233,49 +370,49 @@ func validateChainMarkIncludeExclude(funcs []*provider, canRemoveDesired bool) e
 			fm.include = false
 		}
 	}
-	return checkFlows(remainingFuncs, len(funcs), canRemoveDesired)
+	return checkFlows(remainingFuncs, len(funcs), canRemoveDesired, tr)
 }
 
-func checkFlows(funcs []*provider, numFuncs int, canRemoveDesired bool) error {
+func checkFlows(funcs []*provider, numFuncs int, canRemoveDesired bool, tr *tracer) error {
 	todo := funcs
 	redo := make([]*provider, 0, len(funcs)*6)
 	for len(todo) > 0 {
 		seen := make([]bool, numFuncs)
-		debugf("\tstarting check pass with %d providers", len(todo))
+		tr.debugf("\tstarting check pass with %d providers", len(todo))
 	Todo:
 		for _, fm := range todo {
 			if seen[fm.chainPosition] {
-				debugf("\talready done: %s", fm)
+				tr.debugf("\talready done: %s", fm)
 				continue
 			}
 			seen[fm.chainPosition] = true
 			if fm.cannotInclude != nil {
 				if fm.required {
-					debugf("\tchain invalid required but: %s: %s", fm, fm.cannotInclude)
+					tr.debugf("\tchain invalid required but: %s: %s", fm, fm.cannotInclude)
 					return fm.errorf("required but %s", fm.cannotInclude)
 				}
 				if (fm.wanted || fm.desired) && !canRemoveDesired && fm.d.excluded == nil {
-					debugf("\tchain invalid wanted but: %s: %s", fm, fm.cannotInclude)
+					tr.debugf("\tchain invalid wanted but: %s: %s", fm, fm.cannotInclude)
 					return fm.errorf("wanted but %s", fm.cannotInclude)
 				}
 				if fm.include {
-					debugf("\tprovider now excluded: %s: %s", fm, fm.cannotInclude)
+					tr.debugf("\tprovider now excluded: %s: %s", fm, fm.cannotInclude)
 					fm.include = false
 					redo = append(redo, fm.d.usedBy...)
 				} else {
-					debugf("\tprovider already excluded: %s: %s", fm, fm.cannotInclude)
+					tr.debugf("\tprovider already excluded: %s: %s", fm, fm.cannotInclude)
 				}
 				continue
 			}
 
-			debugf("\tchecking %s", fm)
+			tr.debugf("\tchecking %s", fm)
 
 			// This checks for inputs with no provider
 			for param, errors := range fm.d.usesError {
 				for tc, err := range errors {
 					fm.cannotInclude = err
 					redo = append(redo, fm)
-					debugf("\t\trequire error on %d %s: %s", param, tc, err)
+					tr.debugf("\t\trequire error on %d %s: %s", param, tc, err)
 					continue Todo
 				}
 			}
@@ -287,15 +424,15 @@ func checkFlows(funcs []*provider, numFuncs int, canRemoveDesired bool) error {
 					var extra string
 					for _, p := range plist {
 						if p.include {
-							debugf("\t\t\tfound source for %s %s: %s", param, tc, p)
+							tr.debugf("\t\t\tfound source for %s %s: %s", flowType(param), tc, p)
 							continue Source
 						}
-						debugf("\t\t\tcannot provide %s %s: %s: %s", param, tc, p, p.cannotInclude)
+						tr.debugf("\t\t\tcannot provide %s %s: %s: %s", flowType(param), tc, p, p.cannotInclude)
 						extra = fmt.Sprintf(" (not provided by %s because %s)", p, p.cannotInclude)
 					}
 					fm.cannotInclude = fmt.Errorf("no provider for %s in %s%s", tc, flowType(param), extra)
 					redo = append(redo, fm)
-					debugf("\t\tno source %s %s  %s: %s", param, tc, fm, fm.cannotInclude)
+					tr.debugf("\t\tno source %s %s  %s: %s", flowType(param), tc, fm, fm.cannotInclude)
 					continue Todo
 				}
 			}
@@ -323,29 +460,29 @@ func checkFlows(funcs []*provider, numFuncs int, canRemoveDesired bool) error {
 					var extra string
 					for _, p := range fm.d.usedByDetail[param][tc] {
 						if p.include {
-							debugf("\t\t\tfound consumer of %s %s: %s", param, tc, p)
+							tr.debugf("\t\t\tfound consumer of %s %s: %s", flowType(param), tc, p)
 							continue Param
 						}
-						debugf("\t\t\tcannot consume %s %s: %s: %s", param, tc, p, p.cannotInclude)
+						tr.debugf("\t\t\tcannot consume %s %s: %s: %s", flowType(param), tc, p, p.cannotInclude)
 						extra = fmt.Sprintf(" (not consumed by %s because %s)", p, p.cannotInclude)
 					}
 					fm.cannotInclude = fmt.Errorf("no consumer for %s in %s%s", tc, flowType(param), extra)
 					redo = append(redo, fm)
-					debugf("\t\tnot consumed %s %s %s: %s", param, tc, fm, fm.cannotInclude)
+					tr.debugf("\t\tnot consumed %s %s %s: %s", flowType(param), tc, fm, fm.cannotInclude)
 					continue Todo
 				}
 			}
-			debugf("\t\tprovider still valid: %s", fm)
+			tr.debugf("\t\tprovider still valid: %s", fm)
 		}
 		todo = redo
 		redo = make([]*provider, 0, len(redo)*2)
 	}
-	debugln("\tchain is valid")
+	tr.debugln("\tchain is valid")
 	return nil
 }
 
-func providesReturns(funcs []*provider, initF *provider) error {
-	debugln("calculating provides/returns")
+func providesReturns(funcs []*provider, initF *provider, rules *matchRules, tr *tracer) error {
+	tr.debugln("calculating provides/returns")
 	for _, fm := range funcs {
 		fm.d.usedByDetail = [lastFlowType]map[typeCode][]*provider{}
 		fm.d.usesDetail = [lastFlowType]map[typeCode][]*provider{}
@@ -356,21 +493,21 @@ func providesReturns(funcs []*provider, initF *provider) error {
 	provide := make(interfaceMap)
 	for i, fm := range funcs {
 		if fm.cannotInclude != nil {
-			debugf("\tskipping on downard path %s: %s", fm, fm.cannotInclude)
+			tr.debugf("\tskipping on downard path %s: %s", fm, fm.cannotInclude)
 			continue
 		}
 		if fm.class == invokeFunc && initF != nil {
 			initF.bypassRmap = make(map[typeCode]typeCode)
-			err := requireParameters(initF, provide, bypassParams, outputParams, initF.bypassRmap, "returned value")
+			err := requireParameters(initF, provide, bypassParams, outputParams, initF.bypassRmap, "returned value", rules, tr)
 			if err != nil {
 				return err
 			}
 		}
-		err := requireParameters(fm, provide, inputParams, outputParams, fm.downRmap, "input")
+		err := requireParameters(fm, provide, inputParams, outputParams, fm.downRmap, "input", rules, tr)
 		if err != nil {
 			return err
 		}
-		provideParameters(fm, provide, outputParams, inputParams, i+2)
+		provideParameters(fm, provide, outputParams, inputParams, i+2, tr)
 	}
 
 	// Upwards chain
@@ -378,14 +515,14 @@ func providesReturns(funcs []*provider, initF *provider) error {
 	for i := len(funcs) - 1; i >= 0; i-- {
 		fm := funcs[i]
 		if fm.cannotInclude != nil {
-			debugf("\tskipping on upward path %s: %s", fm, fm.cannotInclude)
+			tr.debugf("\tskipping on upward path %s: %s", fm, fm.cannotInclude)
 			continue
 		}
-		err := requireParameters(fm, returns, receivedParams, returnParams, fm.upRmap, "expected return")
+		err := requireParameters(fm, returns, receivedParams, returnParams, fm.upRmap, "expected return", rules, tr)
 		if err != nil {
 			return err
 		}
-		provideParameters(fm, returns, returnParams, receivedParams, len(funcs)-i+2)
+		provideParameters(fm, returns, returnParams, receivedParams, len(funcs)-i+2, tr)
 	}
 	return nil
 }
@@ -396,8 +533,9 @@ func provideParameters(
 	param flowType,
 	inParam flowType,
 	position int,
+	tr *tracer,
 ) {
-	debugf("\tproviding %s for %s", param, fm)
+	tr.debugf("\tproviding %s for %s", param, fm)
 	incoming := make(map[typeCode]bool)
 	for _, in := range fm.flows[inParam] {
 		incoming[in] = true
@@ -405,10 +543,10 @@ func provideParameters(
 	fm.d.usedByDetail[param] = make(map[typeCode][]*provider)
 	for _, out := range fm.flows[param] {
 		if out == noTypeCode {
-			debugln("\t\tskipping no-type")
+			tr.debugln("\t\tskipping no-type")
 			continue
 		}
-		debugf("\t\tproviding %s from %s", out, fm)
+		tr.debugf("\t\tproviding %s from %s", out, fm)
 		available.Add(out, position, fm)
 	}
 }
@@ -420,18 +558,24 @@ func requireParameters(
 	outParam flowType,
 	rMap map[typeCode]typeCode,
 	purpose string,
+	rules *matchRules,
+	tr *tracer,
 ) error {
-	debugf("\trequire %s for %s", purpose, fm)
+	tr.debugf("\trequire %s for %s", purpose, fm)
 	fm.d.usesError[param] = make(map[typeCode]error)
 	fm.d.usesDetail[param] = make(map[typeCode][]*provider)
 	for _, in := range fm.flows[param] {
 		if in == noTypeCode {
-			debugf("\t\tskipping %s: not a real type", in)
+			tr.debugf("\t\tskipping %s: not a real type", in)
 			continue
 		}
-		found, dependsOn, err := available.bestMatch(in, purpose)
+		found, dependsOn, err := available.bestMatch(in, purpose, rules)
+		var tie matchTieError
+		if errors.As(err, &tie) {
+			return fm.errorf("%s", tie)
+		}
 		if err != nil {
-			debugf("\t\tcannot find %s %s: %s", param, in, err)
+			tr.debugf("\t\tcannot find %s %s: %s", param, in, err)
 			fm.d.usesError[param][in] = err
 			continue
 		}
@@ -440,11 +584,11 @@ func requireParameters(
 		}
 		rMap[in] = found
 		for _, dep := range dependsOn {
-			debugf("\t\tadding dependency for %s: uses %s", in, dep)
+			tr.debugf("\t\tadding dependency for %s: uses %s", in, dep)
 			fm.d.usesDetail[param][in] = append(fm.d.usesDetail[param][in], dep)
 			fm.d.uses = append(fm.d.uses, dep)
 
-			debugf("\t\tadding used-by %s %s: %s", outParam, in, dep)
+			tr.debugf("\t\tadding used-by %s %s: %s", outParam, in, dep)
 			dep.d.usedBy = append(dep.d.usedBy, fm)
 			dep.d.usedByDetail[outParam][in] = append(dep.d.usedByDetail[outParam][in], fm)
 			if dep.d.mustConsumeFlow[outParam] {
@@ -455,8 +599,21 @@ func requireParameters(
 	return nil
 }
 
-func eliminateUnused(check []*provider) {
-	debugln("eliminate those that no longer have any consumers")
+// errNotUsed and errNotNecessary are why providers that could be
+// included are excluded
+var (
+	errNotUsed      = errors.New("not used by any remaining providers")
+	errNotNecessary = errors.New("not required, not desired, not necessary")
+)
+
+// couldInclude is true if fm is included or is only excluded because
+// it isn't needed
+func (fm *provider) couldInclude() bool {
+	return fm.include || fm.cannotInclude == errNotUsed || fm.cannotInclude == errNotNecessary
+}
+
+func eliminateUnused(check []*provider, tr *tracer) {
+	tr.debugln("eliminate those that no longer have any consumers")
 PostCheck:
 	for len(check) > 0 {
 		var fm *provider
@@ -466,20 +623,20 @@ PostCheck:
 		}
 		for _, dep := range fm.d.usedBy {
 			if dep.include {
-				debugf("\t%s included by %s", fm, dep)
+				tr.debugf("\t%s included by %s", fm, dep)
 				continue PostCheck
 			}
 		}
 		fm.include = false
-		fm.cannotInclude = fmt.Errorf("not used by any remaining providers")
+		fm.cannotInclude = errNotUsed
 		fm.d.excluded = fm.cannotInclude
-		debugf("\tno included users for: %s", fm)
+		tr.debugf("\tno included users for: %s", fm)
 		check = append(check, fm.d.uses...)
 	}
 }
 
-func proposeEliminations(funcs []*provider) []*provider {
-	debugln("pick providers that should be considered for exclusion")
+func proposeEliminations(funcs []*provider, tr *tracer) []*provider {
+	tr.debugln("pick providers that should be considered for exclusion")
 	kept := make([]bool, len(funcs))
 	for _, fg := range []struct {
 		direction  string
@@ -503,19 +660,19 @@ func proposeEliminations(funcs []*provider) []*provider {
 			var fm *provider
 			fm, toKeep = toKeep[0], toKeep[1:]
 			if keep[fm.chainPosition] {
-				debugf("\talready kept: %s", fm)
+				tr.debugf("\talready kept: %s", fm)
 				continue
 			}
-			debugf("\tkeeping %s %s", fg.direction, fm)
+			tr.debugf("\tkeeping %s %s", fg.direction, fm)
 			keep[fm.chainPosition] = true
 			kept[fm.chainPosition] = true
 			for _, param := range fg.flowGroups {
 				for tc, users := range fm.d.usesDetail[param] {
-					debugf("\t\tsourcing %s %s", param, tc)
+					tr.debugf("\t\tsourcing %s %s", param, tc)
 					deps := make([]*provider, 0, len(users))
 					for _, dep := range users {
 						if dep.cannotInclude == nil && dep.d.excluded == nil {
-							debugf("\t\t\tcan get it from %s", dep)
+							tr.debugf("\t\t\tcan get it from %s", dep)
 							deps = append(deps, dep)
 						}
 					}
@@ -527,13 +684,13 @@ func proposeEliminations(funcs []*provider) []*provider {
 							k = deps[0]
 						}
 						if !keep[k.chainPosition] {
-							debugf("\t\t\tfor %s %s, keeping %s", param, tc, k)
+							tr.debugf("\t\t\tfor %s %s, keeping %s", param, tc, k)
 							toKeep = append(toKeep, k)
 							if k.whyIncluded == "" {
 								k.whyIncluded = fmt.Sprintf("used by %s (%s)", fm, fm.whyIncluded)
 							}
 						} else {
-							debugf("\t\t\tfor %s %s, no need to keep %s", param, tc, k)
+							tr.debugf("\t\t\tfor %s %s, no need to keep %s", param, tc, k)
 						}
 					}
 				}
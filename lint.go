@@ -0,0 +1,172 @@
+package nject
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WarningKind categorizes the warnings returned by Lint
+type WarningKind string
+
+const (
+	// WarnBindError is used when the chain would not bind.  The
+	// other checks are skipped.
+	WarnBindError WarningKind = "bind-error"
+	// WarnExcluded is used for providers that were not included in
+	// the chain
+	WarnExcluded WarningKind = "excluded"
+	// WarnClosestWins is used when a provider receives a value that is
+	// provided by more than one provider and the closest one was silently
+	// chosen.
+	WarnClosestWins WarningKind = "closest-wins"
+	// WarnShunIncluded is used for providers marked Shun that were
+	// included anyway
+	WarnShunIncluded WarningKind = "shun-included"
+	// WarnReorderDidNotMove is used for providers marked Reorder that
+	// ended up in their original position
+	WarnReorderDidNotMove WarningKind = "reorder-did-not-move"
+	// WarnMemoizeInRun is used for providers marked Memoize that are in
+	// the RUN set where they will remember every combination of inputs
+	WarnMemoizeInRun WarningKind = "memoize-in-run"
+	// WarnDuplicateTypeName is used when a type in the chain has a name
+	// that refers to more than one type
+	WarnDuplicateTypeName WarningKind = "duplicate-type-name"
+)
+
+// Warning describes a possible problem with an injection chain.
+type Warning struct {
+	Kind WarningKind
+	// Provider describes the provider that the warning is about.  It
+	// is empty for warnings that are not about a specific provider.
+	Provider string
+	Message  string
+}
+
+func (w Warning) String() string {
+	if w.Provider == "" {
+		return fmt.Sprintf("%s: %s", w.Kind, w.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", w.Kind, w.Provider, w.Message)
+}
+
+// Lint examines the injection chain that would be created by
+// binding the collection with invokeFunc and initFunc (which may be nil)
+// and reports things that are not errors but may be mistakes.  Lint does
+// not bind the collection.
+//
+// If the collection cannot be bound, Lint returns a single warning
+// of kind WarnBindError.
+func Lint(c *Collection, invokeFunc any, initFunc any) []Warning {
+	invokeF := newProvider(invokeFunc, -1, c.name+" invoke func")
+	var initF *provider
+	if initFunc != nil {
+		initF = newProvider(initFunc, -1, c.name+" initialization func")
+	}
+	var warnings []Warning
+	err := doBind(c, invokeF, initF, false, newBindTracer(c), func(funcs []*provider) {
+		warnings = lintProviders(funcs)
+	})
+	if err != nil {
+		return []Warning{{
+			Kind:    WarnBindError,
+			Message: err.Error(),
+		}}
+	}
+	return warnings
+}
+
+func lintProviders(funcs []*provider) []Warning {
+	var warnings []Warning
+	add := func(kind WarningKind, fm *provider, format string, args ...any) {
+		w := Warning{
+			Kind:    kind,
+			Message: fmt.Sprintf(format, args...),
+		}
+		if fm != nil {
+			w.Provider = fm.String()
+		}
+		warnings = append(warnings, w)
+	}
+
+	for _, fm := range funcs {
+		if fm.isSynthetic {
+			continue
+		}
+		switch {
+		case !fm.include:
+			add(WarnExcluded, fm, "not included: %s", fm.cannotInclude)
+			continue
+		case fm.shun && !fm.isDefault:
+			add(WarnShunIncluded, fm, "marked Shun but included: %s", fm.whyIncluded)
+		}
+		if fm.reorder && !fm.d.reorderMoved {
+			add(WarnReorderDidNotMove, fm, "marked Reorder but was not moved")
+		}
+		if (fm.memoize || fm.memoized) && fm.group == runGroup {
+			add(WarnMemoizeInRun, fm, "marked Memoize but is in the RUN set so it will remember every combination of inputs")
+		}
+	}
+
+	// Walk down the chain tracking the included providers of each type.
+	// A provider that consumes and provides the same type replaces the
+	// earlier providers, otherwise the closest provider silently wins.
+	producers := make(map[typeCode][]*provider)
+	reported := make(map[typeCode]bool)
+	for _, fm := range funcs {
+		if !fm.include {
+			continue
+		}
+		consumes := make(map[typeCode]bool)
+		for _, in := range fm.flows[inputParams] {
+			tc := in
+			if found, ok := fm.downRmap[in]; ok {
+				tc = found
+			}
+			consumes[tc] = true
+			plist := producers[tc]
+			if len(plist) < 2 || reported[tc] {
+				continue
+			}
+			closest := plist[len(plist)-1]
+			others := make([]string, 0, len(plist)-1)
+			for _, p := range plist[:len(plist)-1] {
+				others = append(others, p.String())
+			}
+			reported[tc] = true
+			add(WarnClosestWins, fm, "receives %s from %s, ignoring %s", tc, closest, strings.Join(others, ", "))
+		}
+		for _, out := range fm.flows[outputParams] {
+			if out == noTypeCode || out == unusedTypeCode {
+				continue
+			}
+			if consumes[out] {
+				producers[out] = []*provider{fm}
+			} else {
+				producers[out] = append(producers[out], fm)
+			}
+		}
+	}
+
+	checked := make(map[string]bool)
+	var names []string
+	for _, fm := range funcs {
+		for _, flow := range fm.flows {
+			for _, tc := range flow {
+				name := tc.String()
+				if checked[name] {
+					continue
+				}
+				checked[name] = true
+				if isDuplicateTypeName(name) {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(WarnDuplicateTypeName, nil, "type name %s refers to more than one type", name)
+	}
+	return warnings
+}
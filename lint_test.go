@@ -0,0 +1,121 @@
+package nject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lintKinds(warnings []Warning) map[WarningKind][]Warning {
+	m := make(map[WarningKind][]Warning)
+	for _, w := range warnings {
+		m[w.Kind] = append(m[w.Kind], w)
+	}
+	return m
+}
+
+func TestLintClean(t *testing.T) {
+	t.Parallel()
+	var invoke func(s0) s2
+	warnings := Lint(Sequence(t.Name(),
+		func(s s0) s1 { return s1(s) },
+		func(s s1) s2 { return s2(s) },
+	), &invoke, nil)
+	assert.Empty(t, warnings)
+}
+
+func TestLintBindError(t *testing.T) {
+	t.Parallel()
+	var invoke func()
+	warnings := Lint(Sequence(t.Name(),
+		func(_ s1) {},
+	), &invoke, nil)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, WarnBindError, warnings[0].Kind)
+	assert.Contains(t, warnings[0].String(), "bind-error: ")
+}
+
+func TestLintWarnings(t *testing.T) {
+	t.Parallel()
+	var invoke func(s0)
+	var init func(s5)
+	warnings := Lint(Sequence(t.Name(),
+		Provide("unused", func() s3 { return "" }),
+		Provide("first-s1", func(s s0) s1 { return s1(s) }),
+		Provide("uses-first", func(s s1) s8 { return s8(s) }),
+		Provide("second-s1", func() s1 { return "" }),
+		Provide("shunned", Shun(func(s s1) s2 { return s2(s) })),
+		Provide("memoized", Memoize(func(s s1) s4 { return s4(s) })),
+		Provide("static", func(s s5) s6 { return s6(s) }),
+		Provide("reorder", Reorder(func(s s6) s7 { return s7(s) })),
+		func(_ s2, _ s4, _ s7, _ s8) {},
+	), &invoke, &init)
+	for _, w := range warnings {
+		t.Log(w)
+	}
+	kinds := lintKinds(warnings)
+
+	require.Len(t, kinds[WarnExcluded], 1)
+	assert.Contains(t, kinds[WarnExcluded][0].Provider, "unused")
+
+	require.Len(t, kinds[WarnClosestWins], 1)
+	assert.Contains(t, kinds[WarnClosestWins][0].Provider, "shunned")
+	assert.Contains(t, kinds[WarnClosestWins][0].Message, "from injector: TestLintWarnings/second-s1 [")
+	assert.Contains(t, kinds[WarnClosestWins][0].Message, "ignoring injector: TestLintWarnings/first-s1 [")
+
+	require.Len(t, kinds[WarnShunIncluded], 1)
+	assert.Contains(t, kinds[WarnShunIncluded][0].Provider, "shunned")
+
+	require.Len(t, kinds[WarnMemoizeInRun], 1)
+	assert.Contains(t, kinds[WarnMemoizeInRun][0].Provider, "memoized")
+
+	require.Len(t, kinds[WarnReorderDidNotMove], 1)
+	assert.Contains(t, kinds[WarnReorderDidNotMove][0].Provider, "reorder")
+
+	assert.Empty(t, kinds[WarnDuplicateTypeName])
+}
+
+func TestLintReorderMoved(t *testing.T) {
+	t.Parallel()
+	var invoke func()
+	warnings := Lint(Sequence(t.Name(),
+		Reorder(func(s s1) s2 { return s2(s) }),
+		func() s1 { return "" },
+		func(_ s2) {},
+	), &invoke, nil)
+	assert.Empty(t, lintKinds(warnings)[WarnReorderDidNotMove])
+}
+
+func TestLintDuplicateTypeName(t *testing.T) {
+	t.Parallel()
+	makeFirst := func() any {
+		type dup int
+		return func() (dup, chan dup) { return 1, nil }
+	}
+	makeSecond := func() any {
+		type dup string
+		return func() (dup, chan dup) { return "", nil }
+	}
+	var invoke func()
+	warnings := Lint(Sequence(t.Name(),
+		makeFirst(),
+		makeSecond(),
+		func() {},
+	), &invoke, nil)
+	kinds := lintKinds(warnings)
+	require.Len(t, kinds[WarnDuplicateTypeName], 2, "warnings: %v", warnings)
+	assert.Equal(t, "duplicate-type-name: type name chan nject/v2.dup refers to more than one type", kinds[WarnDuplicateTypeName][0].String())
+	assert.Equal(t, "duplicate-type-name: type name nject/v2.dup refers to more than one type", kinds[WarnDuplicateTypeName][1].String())
+}
+
+func TestLintClosestWinsPerType(t *testing.T) {
+	t.Parallel()
+	var invoke func()
+	warnings := Lint(Sequence(t.Name(),
+		Required(func() (s1, s2) { return "", "" }),
+		func() (s1, s2) { return "", "" },
+		func(_ s1, _ s2) {},
+	), &invoke, nil)
+	assert.Len(t, lintKinds(warnings)[WarnClosestWins], 2, "warnings: %v", warnings)
+}
@@ -0,0 +1,77 @@
+package nject
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// njectFuncPrefix is the prefix of the fully qualified names of the functions
+// that are part of this package.  It is used to skip over nject's own frames
+// when looking for where a provider came from.
+var njectFuncPrefix = reflect.TypeOf(provider{}).PkgPath() + "."
+
+const maxLocationDepth = 32
+
+// callerLocation returns the file:line of the closest stack frame that is
+// not inside nject itself. Tests that are part of the nject package count
+// as being outside of nject.
+func callerLocation() string {
+	pcs := make([]uintptr, maxLocationDepth)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, njectFuncPrefix) || strings.HasSuffix(frame.File, "_test.go") {
+			return formatLocation(frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// funcLocation returns the file:line where a function is defined.  It returns
+// "" for anything that is not a function or if the location cannot be determined.
+func funcLocation(fn any) string {
+	v := reflect.ValueOf(fn)
+	if !v.IsValid() || v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	}
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return ""
+	}
+	file, line := f.FileLine(f.Entry())
+	if file == "" || strings.HasPrefix(file, "<") {
+		// <autogenerated> wrappers for method values
+		return ""
+	}
+	return formatLocation(file, line)
+}
+
+func formatLocation(file string, line int) string {
+	if file == "" {
+		return ""
+	}
+	return filepath.Base(file) + ":" + strconv.Itoa(line)
+}
+
+// location describes where a provider was registered with nject and where
+// its function was defined.  It returns "" if neither is known.
+func (fm *provider) location() string {
+	switch {
+	case fm.registeredAt == "" && fm.definedAt == "":
+		return ""
+	case fm.registeredAt == fm.definedAt:
+		return fm.registeredAt
+	case fm.definedAt == "":
+		return "registered " + fm.registeredAt
+	case fm.registeredAt == "":
+		return "defined " + fm.definedAt
+	default:
+		return "registered " + fm.registeredAt + ", defined " + fm.definedAt
+	}
+}
@@ -0,0 +1,95 @@
+package nject
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var locationRE = regexp.MustCompile(` \((?:registered |defined )?[^()]+\.go:\d+(?:, defined [^()]+\.go:\d+)?\)`)
+
+// stripLocations removes the source locations from provider descriptions
+// so that they can be compared without depending upon line numbers.
+func stripLocations(list []string) []string {
+	stripped := make([]string, len(list))
+	for i, s := range list {
+		stripped[i] = locationRE.ReplaceAllString(s, "")
+	}
+	return stripped
+}
+
+func namedLocationTestFunc(s s0) s1 { return s1(s) }
+
+// namedLocationTestWhere finds where namedLocationTestFunc is defined
+// by reading the source so that the tests do not depend upon its
+// line number.
+func namedLocationTestWhere(t *testing.T) string {
+	src, err := os.ReadFile("location_test.go")
+	require.NoError(t, err)
+	i := strings.Index(string(src), "\nfunc namedLocationTestFunc(")
+	require.NotEqual(t, -1, i)
+	return "location_test.go:" + strconv.Itoa(strings.Count(string(src[:i]), "\n")+2)
+}
+
+func TestLocationInDebugging(t *testing.T) {
+	t.Parallel()
+	where := regexp.QuoteMeta(namedLocationTestWhere(t))
+	var called bool
+	require.NoError(t, Run(t.Name(),
+		s0("x"),
+		Provide("named", namedLocationTestFunc),
+		func(_ s1, d *Debugging) {
+			called = true
+			var found bool
+			for _, s := range d.Included {
+				if strings.Contains(s, "named [") {
+					found = true
+					assert.Regexp(t, `\(registered location_test\.go:\d+, defined `+where+`\)$`, s)
+				}
+			}
+			assert.True(t, found, "named provider included")
+			for _, s := range d.IncludeExclude {
+				if strings.Contains(s, "Debugging [") {
+					assert.NotContains(t, s, "Debugging [func() *nject.Debugging] (", "synthetic providers have no location")
+				}
+			}
+			assert.Regexp(t, `// included; registered location_test\.go:\d+, defined `+where+`\n`, d.Reproduce)
+		},
+	))
+	assert.True(t, called)
+}
+
+func TestLocationInErrors(t *testing.T) {
+	t.Parallel()
+	err := Run(t.Name(),
+		Provide("missing-input", func(_ s2) {}),
+	)
+	require.Error(t, err)
+	assert.Regexp(t, `missing-input \[func\(nject\.s2\)\] \(location_test\.go:\d+\)`, err.Error())
+}
+
+func TestLocationFormats(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		registered string
+		defined    string
+		want       string
+	}{
+		{},
+		{registered: "a.go:1", defined: "a.go:1", want: "a.go:1"},
+		{registered: "a.go:1", want: "registered a.go:1"},
+		{defined: "b.go:2", want: "defined b.go:2"},
+		{registered: "a.go:1", defined: "b.go:2", want: "registered a.go:1, defined b.go:2"},
+	}
+	for _, tc := range cases {
+		fm := &provider{registeredAt: tc.registered, definedAt: tc.defined}
+		assert.Equal(t, tc.want, fm.location())
+	}
+	assert.Empty(t, funcLocation(7), "not a function")
+	assert.Equal(t, namedLocationTestWhere(t), funcLocation(namedLocationTestFunc))
+}
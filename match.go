@@ -8,6 +8,8 @@ package nject
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 type interfaceMap map[typeCode]*interfaceMatchData
@@ -38,19 +40,41 @@ func (m interfaceMap) Add(t typeCode, layer int, fm *provider) {
 	}
 }
 
-func aGreaterBInts(a []int, b []int) bool {
+// compareInts compares scores element by element
+func compareInts(a []int, b []int) int {
 	for i := 0; i < len(a) && i < len(b); i++ {
 		if a[i] > b[i] {
-			return true
+			return 1
 		}
 		if a[i] < b[i] {
-			return false
+			return -1
 		}
 	}
-	return len(a) >= len(b)
+	switch {
+	case len(a) > len(b):
+		return 1
+	case len(a) < len(b):
+		return -1
+	default:
+		return 0
+	}
+}
+
+func (m interfaceMap) bestMatch(match typeCode, purpose string, rules *matchRules) (typeCode, []*provider, error) {
+	return m.bestMatchAmong(match, purpose, rules, func(*interfaceMatchData) bool { return true })
 }
 
-func (m interfaceMap) bestMatch(match typeCode, purpose string) (typeCode, []*provider, error) {
+// bestLooseMatch is like bestMatch except that when matching an interface
+// only types with providers that are Loose for that interface are considered.
+// Reorder uses this: it can move a consumer so that a non-Loose implementation
+// of the interface is not closer than a Loose one.
+func (m interfaceMap) bestLooseMatch(match typeCode, purpose string, rules *matchRules) (typeCode, []*provider, error) {
+	return m.bestMatchAmong(match, purpose, rules, func(imd *interfaceMatchData) bool {
+		return len(looseOnly(match, imd.plist)) > 0
+	})
+}
+
+func (m interfaceMap) bestMatchAmong(match typeCode, purpose string, rules *matchRules, eligible func(*interfaceMatchData) bool) (typeCode, []*provider, error) {
 	d, found := m[match]
 	if found {
 		d.consumed = true
@@ -59,38 +83,52 @@ func (m interfaceMap) bestMatch(match typeCode, purpose string) (typeCode, []*pr
 	if match.Type().Kind() != reflect.Interface {
 		return match, nil, fmt.Errorf("has no match for its %s parameter %s", purpose, match)
 	}
-	// What is the best match?
-	// (*) Highest layer number
-	// (*) Same package path for source and destination
-	// (*) Highest method count
-	// (*) Lowest typeCode value
+	if bound, ok := rules.boundTo(match); ok {
+		// Explicit bindings do not require Loose
+		d, found := m[bound]
+		if !found {
+			return match, nil, fmt.Errorf("has no match for its %s parameter %s (bound to %s which is not provided)", purpose, match, bound)
+		}
+		d.consumed = true
+		return bound, d.plist, nil
+	}
+	// What is the best match?  That is decided by the MatchPolicy.
 	var best struct {
 		tc    typeCode
 		imd   *interfaceMatchData
 		score []int
+		tied  []typeCode
 	}
-	score := func(tc typeCode, imd *interfaceMatchData) []int {
-		samePathScore := 0
-		if imd.typeCode.Type().PkgPath() == match.Type().PkgPath() {
-			samePathScore = 1
-		}
-		return []int{imd.layer, samePathScore, imd.typeCode.Type().NumMethod(), int(tc)}
-	}
+	policy := rules.matchPolicy()
 	for tc, imd := range m {
-		if !imd.typeCode.Type().Implements(match.Type()) {
+		if !imd.typeCode.Type().Implements(match.Type()) || !eligible(imd) {
 			continue
 		}
-		s := score(tc, imd)
-		if best.imd == nil || aGreaterBInts(s, best.score) {
+		s := policy(match.Type(), MatchCandidate{
+			Type:     imd.typeCode.Type(),
+			Position: imd.layer,
+		})
+		switch {
+		case best.imd == nil || compareInts(s, best.score) > 0:
 			best.tc = tc
 			best.imd = imd
 			best.score = s
-			continue
+			best.tied = nil
+		case compareInts(s, best.score) == 0:
+			best.tied = append(best.tied, tc)
 		}
 	}
 	if best.imd == nil {
 		return match, nil, fmt.Errorf("has no match for its %s parameter %s", purpose, match)
 	}
+	if len(best.tied) != 0 {
+		tied := []string{best.tc.String()}
+		for _, tc := range best.tied {
+			tied = append(tied, tc.String())
+		}
+		sort.Strings(tied)
+		return match, nil, matchTieError(fmt.Sprintf("has more than one equally good match for its %s parameter %s: %s", purpose, match, strings.Join(tied, ", ")))
+	}
 	loose := looseOnly(match, best.imd.plist)
 	if len(loose) == 0 {
 		return match, nil, fmt.Errorf("has no match for its %s parameter %s (ignoring %s provided by %s)", purpose, match, best.imd.typeCode, best.imd.plist[0])
@@ -98,6 +136,33 @@ func (m interfaceMap) bestMatch(match typeCode, purpose string) (typeCode, []*pr
 	return best.tc, loose, nil
 }
 
+// matchTieError is returned by bestMatch when the MatchPolicy cannot
+// pick between candidates.  Unlike other match errors, it fails Bind
+// rather than excluding the provider.
+type matchTieError string
+
+func (e matchTieError) Error() string { return string(e) }
+
+// notLoose returns the providers of types that implement the match
+// interface but are not Loose for it and thus cannot be used to fill it.
+func (m interfaceMap) notLoose(match typeCode) []*provider {
+	if match.Type().Kind() != reflect.Interface {
+		return nil
+	}
+	var plist []*provider
+	for tc, imd := range m {
+		if tc == match || !imd.typeCode.Type().Implements(match.Type()) {
+			continue
+		}
+		for _, fm := range imd.plist {
+			if _, ok := fm.loose[match]; !ok {
+				plist = append(plist, fm)
+			}
+		}
+	}
+	return plist
+}
+
 func looseOnly(match typeCode, plist []*provider) []*provider {
 	loose := make([]*provider, 0, len(plist))
 	for _, fm := range plist {
@@ -107,3 +172,111 @@ func looseOnly(match typeCode, plist []*provider) []*provider {
 	}
 	return loose
 }
+
+// MatchCandidate is a type that could be used to fill an interface
+// parameter.
+type MatchCandidate struct {
+	Type reflect.Type // the type that implements the interface
+	// Position is the position in the injection chain of the
+	// first provider of Type.  Higher is closer to the consumer.
+	Position int
+}
+
+// MatchPolicy scores a candidate for filling an interface parameter
+// when there is no provider of the exact interface type.  Scores are
+// compared element by element: the highest score wins.  If more than
+// one candidate has the highest score, the match fails.
+type MatchPolicy func(iface reflect.Type, candidate MatchCandidate) []int
+
+// DefaultMatchPolicy prefers, in order: providers that are closer,
+// types that are in the same package as the interface, and types
+// that have more methods.
+func DefaultMatchPolicy(iface reflect.Type, candidate MatchCandidate) []int {
+	samePathScore := 0
+	if candidate.Type.PkgPath() == iface.PkgPath() {
+		samePathScore = 1
+	}
+	return []int{candidate.Position, samePathScore, candidate.Type.NumMethod()}
+}
+
+// WithMatchPolicy is a directive that sets the MatchPolicy for an
+// injection chain.  Like TraceTo, it is not a provider and it applies
+// to the entire chain.  If there is more than one, the last one is used.
+func WithMatchPolicy(policy MatchPolicy) Provider {
+	fm := newProvider(func() {}, -1, "WithMatchPolicy")
+	fm.matchRule = func(rules *matchRules) error {
+		rules.policy = policy
+		return nil
+	}
+	return fm
+}
+
+// BindInterface is a directive that says that I should be filled by
+// Impl throughout the injection chain.  Providers of Impl do not need
+// to be Loose for I and other types that implement I are ignored.
+// Like TraceTo, it is not a provider.  It is an error if Impl does not
+// implement I or if I is bound to more than one type.
+func BindInterface[I any, Impl any]() Provider {
+	iface := reflect.TypeOf((*I)(nil)).Elem()
+	impl := reflect.TypeOf((*Impl)(nil)).Elem()
+	fm := newProvider(func() {}, -1, "BindInterface")
+	fm.matchRule = func(rules *matchRules) error {
+		if iface.Kind() != reflect.Interface {
+			return fmt.Errorf("BindInterface: %s is not an interface", iface)
+		}
+		if !impl.Implements(iface) {
+			return fmt.Errorf("BindInterface: %s does not implement %s", impl, iface)
+		}
+		if rules.bindings == nil {
+			rules.bindings = make(map[typeCode]typeCode)
+		}
+		ifaceCode, implCode := getTypeCode(iface), getTypeCode(impl)
+		if current, ok := rules.bindings[ifaceCode]; ok && current != implCode {
+			return fmt.Errorf("BindInterface: %s is bound to both %s and %s", iface, current, impl)
+		}
+		rules.bindings[ifaceCode] = implCode
+		return nil
+	}
+	return fm
+}
+
+// matchRules are the chain-wide adjustments to matching set by
+// BindInterface and WithMatchPolicy.  A nil *matchRules is valid.
+type matchRules struct {
+	bindings map[typeCode]typeCode
+	policy   MatchPolicy
+}
+
+// newMatchRules collects the matching directives in a collection.
+// It returns nil if there are none.
+func newMatchRules(c *Collection) (*matchRules, error) {
+	var rules *matchRules
+	for _, fm := range c.contents {
+		if fm.matchRule == nil {
+			continue
+		}
+		if rules == nil {
+			rules = &matchRules{}
+		}
+		err := fm.matchRule(rules)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+func (rules *matchRules) boundTo(iface typeCode) (typeCode, bool) {
+	if rules == nil {
+		return 0, false
+	}
+	tc, ok := rules.bindings[iface]
+	return tc, ok
+}
+
+func (rules *matchRules) matchPolicy() MatchPolicy {
+	if rules == nil || rules.policy == nil {
+		return DefaultMatchPolicy
+	}
+	return rules.policy
+}
@@ -74,7 +74,7 @@ func TestBestMatch(t *testing.T) {
 				for tc, d := range m {
 					t.Logf("\tm[%s] = %s (%s) %d", tc.Type(), d.name, d.typeCode.Type(), d.layer)
 				}
-				got, _, err := m.bestMatch(tc, "searching for "+test.Name)
+				got, _, err := m.bestMatch(tc, "searching for "+test.Name, nil)
 				require.NoError(t, err)
 				assert.Equal(t, test.Want.String(), got.Type().String(), test.Name)
 			}
@@ -86,3 +86,85 @@ func TestBestMatch(t *testing.T) {
 		}
 	})
 }
+
+func TestBindInterface(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got int
+		require.NoError(t, Run(t.Name(),
+			func() *doesI { return &doesI{i: 1} },
+			func() *doesJ { return &doesJ{j: 1} },
+			BindInterface[interfaceI, *doesI](),
+			func(x interfaceI) { got = x.I() },
+		))
+		assert.Equal(t, 2, got, "bound to doesI even though doesJ is closer")
+	})
+}
+
+func TestBindInterfaceErrors(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		err := Run(t.Name(),
+			BindInterface[interfaceI, s1](),
+			func() {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "BindInterface: nject.s1 does not implement nject.interfaceI")
+
+		err = Run(t.Name(),
+			BindInterface[interfaceI, *doesI](),
+			BindInterface[interfaceI, *doesJ](),
+			func() {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is bound to both")
+
+		err = Run(t.Name(),
+			func() *doesJ { return &doesJ{j: 1} },
+			BindInterface[interfaceI, *doesI](),
+			func(x interfaceI) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bound to *nject/v2.doesI which is not provided")
+	})
+}
+
+func TestMatchTie(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		err := Run(t.Name(),
+			Loose[interfaceI](func() (*doesI, *doesJ) { return &doesI{i: 1}, &doesJ{j: 1} }),
+			func(x interfaceI) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has more than one equally good match for its input parameter nject/v2.interfaceI: ")
+
+		err = Run(t.Name(),
+			Loose[interfaceI](func() (*doesI, *doesJ) { return &doesI{i: 1}, &doesJ{j: 1} }),
+			Provide("not-required", func(_ interfaceI) s1 { return "" }),
+			func() {},
+		)
+		require.Error(t, err, "the tie is reported even though the consumer is not required")
+		assert.Contains(t, err.Error(), "not-required [")
+		assert.Contains(t, err.Error(), "has more than one equally good match for its input parameter nject/v2.interfaceI: ")
+	})
+}
+
+func TestMatchPolicy(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got int
+		var candidates []string
+		require.NoError(t, Run(t.Name(),
+			Loose[interfaceI](func() (*doesI, *doesJ) { return &doesI{i: 1}, &doesJ{j: 1} }),
+			WithMatchPolicy(func(iface reflect.Type, candidate MatchCandidate) []int {
+				assert.Equal(t, interfaceIType, iface)
+				candidates = append(candidates, candidate.Type.String())
+				if candidate.Type == reflect.TypeOf(dj) {
+					return []int{1}
+				}
+				return []int{0}
+			}),
+			func(x interfaceI) { got = x.I() },
+		))
+		assert.Equal(t, 3, got)
+		assert.Contains(t, candidates, "*nject.doesI")
+		assert.Contains(t, candidates, "*nject.doesJ")
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"sync/atomic"
 )
 
@@ -12,11 +13,16 @@ var idCounter int32
 
 // provider is an annotated reference to a provider
 type provider struct {
-	origin string
-	index  int
-	fn     any
-	id     int32
-	fatal  error // set for delayed errors
+	origin  string
+	parents []string // names of the enclosing collections, outermost first
+	index   int
+	fn      any
+	id      int32
+	fatal   error // set for delayed errors
+
+	// where the provider came from (see location.go)
+	registeredAt string // file:line where it was given to nject
+	definedAt    string // file:line where the function was defined
 
 	// user annotations (match these in debug.go)
 	nonFinal            bool
@@ -29,6 +35,9 @@ type provider struct {
 	reorder             bool
 	desired             bool
 	shun                bool
+	strict              bool
+	isDefault           bool
+	isOverride          bool
 	notCacheable        bool
 	mustConsume         map[typeCode]struct{}
 	consumptionOptional map[typeCode]struct{}
@@ -38,7 +47,27 @@ type provider struct {
 	replaceByName       string
 	insertBeforeName    string
 	insertAfterName     string
+	replaceMatching     string
+	insertBeforeMatch   string
+	insertAfterMatch    string
+	replaceProviderOf   typeCode
+	insertBeforeOf      typeCode
+	insertAfterOf       typeCode
+	removeName          string
+	sealed              []sealedBy // set by Seal, innermost first
+	runAfter            []string   // set by After
+	runBefore           []string   // set by Before
 	shadowingAllowed    map[typeCode]struct{}
+	exclusive           map[typeCode]struct{}
+	traceTo             *traceTarget              // set by TraceTo
+	matchRule           func(*matchRules) error   // set by BindInterface and WithMatchPolicy
+	conversion          *conversion               // set by AutoDeref, AutoAddr, and Convert
+	spreadDone          bool                      // fn has been rewritten by Spread or for Out
+	inStructs           *inStructs                // set when parameters embed In
+	optionalMissing     map[reflect.Type]struct{} // set when optional inputs are resolved
+	probeOf             *provider                 // set on copies bound while resolving optional inputs
+	optionalIDs         *sync.Map                 // ids of rebuilds without optional inputs, shared by copies
+	note                string                    // set by newNote
 
 	// added by characterize
 	memoized    bool
@@ -79,10 +108,14 @@ func (fm *provider) copy() *provider {
 		return nil
 	}
 	return &provider{
+		fatal:               fm.fatal,
 		origin:              fm.origin,
+		parents:             fm.parents,
 		index:               fm.index,
 		fn:                  fm.fn,
 		id:                  fm.id,
+		registeredAt:        fm.registeredAt,
+		definedAt:           fm.definedAt,
 		nonFinal:            fm.nonFinal,
 		cacheable:           fm.cacheable,
 		mustCache:           fm.mustCache,
@@ -93,6 +126,9 @@ func (fm *provider) copy() *provider {
 		reorder:             fm.reorder,
 		desired:             fm.desired,
 		shun:                fm.shun,
+		strict:              fm.strict,
+		isDefault:           fm.isDefault,
+		isOverride:          fm.isOverride,
 		notCacheable:        fm.notCacheable,
 		mustConsume:         mapCopy(fm.mustConsume),
 		consumptionOptional: mapCopy(fm.consumptionOptional),
@@ -108,7 +144,27 @@ func (fm *provider) copy() *provider {
 		replaceByName:       fm.replaceByName,
 		insertBeforeName:    fm.insertBeforeName,
 		insertAfterName:     fm.insertAfterName,
+		replaceMatching:     fm.replaceMatching,
+		insertBeforeMatch:   fm.insertBeforeMatch,
+		insertAfterMatch:    fm.insertAfterMatch,
+		replaceProviderOf:   fm.replaceProviderOf,
+		insertBeforeOf:      fm.insertBeforeOf,
+		insertAfterOf:       fm.insertAfterOf,
+		removeName:          fm.removeName,
+		sealed:              fm.sealed,
+		runAfter:            append([]string(nil), fm.runAfter...),
+		runBefore:           append([]string(nil), fm.runBefore...),
 		shadowingAllowed:    mapCopy(fm.shadowingAllowed),
+		exclusive:           mapCopy(fm.exclusive),
+		traceTo:             fm.traceTo,
+		matchRule:           fm.matchRule,
+		conversion:          fm.conversion,
+		spreadDone:          fm.spreadDone,
+		inStructs:           fm.inStructs,
+		optionalMissing:     fm.optionalMissing,
+		probeOf:             fm.probeOf,
+		optionalIDs:         fm.optionalIDs,
+		note:                fm.note,
 	}
 }
 
@@ -145,19 +201,28 @@ func newProvider(fn any, index int, origin string) *provider {
 			return newProvider(c.contents[0], index, origin)
 		}
 		return &provider{
-			origin: origin,
-			index:  index,
-			fn:     nil,
-			id:     atomic.AddInt32(&idCounter, 1),
-			fatal:  fmt.Errorf("cannot turn Collection into a function"),
+			origin:       origin,
+			index:        index,
+			fn:           nil,
+			id:           atomic.AddInt32(&idCounter, 1),
+			fatal:        fmt.Errorf("cannot turn Collection into a function"),
+			registeredAt: callerLocation(),
 		}
 	}
-	return &provider{
-		origin: origin,
-		index:  index,
-		fn:     fn,
-		id:     atomic.AddInt32(&idCounter, 1),
+	fm := &provider{
+		origin:       origin,
+		index:        index,
+		fn:           fn,
+		id:           atomic.AddInt32(&idCounter, 1),
+		registeredAt: callerLocation(),
+		definedAt:    funcLocation(fn),
+	}
+	fm.spreadOut()
+	fm.gatherIn()
+	if fm.optionalInputs() != nil {
+		fm.optionalIDs = new(sync.Map)
 	}
+	return fm
 }
 
 func (fm *provider) String() string {
@@ -176,10 +241,22 @@ func (fm *provider) String() string {
 	if fm.class != unsetClassType {
 		class = fm.class.String() + ": "
 	}
+	var loc string
+	if l := fm.location(); l != "" {
+		loc = " (" + l + ")"
+	}
 	if fm.index >= 0 {
-		return fmt.Sprintf("%s%s(%d) [%s]", class, fm.origin, fm.index, t)
+		return fmt.Sprintf("%s%s(%d) [%s]%s", class, fm.path(), fm.index, t, loc)
 	}
-	return fmt.Sprintf("%s%s [%s]", class, fm.origin, t)
+	return fmt.Sprintf("%s%s [%s]%s", class, fm.path(), t, loc)
+}
+
+// newNote creates a placeholder provider that records a message
+// in the trace when the chain is bound.
+func newNote(note string) *provider {
+	fm := newProvider(func() {}, -1, "note")
+	fm.note = note
+	return fm
 }
 
 func (fm *provider) errorf(format string, args ...any) error {
@@ -188,15 +265,34 @@ func (fm *provider) errorf(format string, args ...any) error {
 
 // This characterizes all the providers and flattens the collection into
 // a couple of lists of providers: providers that run before invoke; and
-// providers that run after invoke.
-func (c Collection) characterizeAndFlatten(nonStaticTypes map[typeCode]bool) ([]*provider, []*provider, error) {
-	debugln("BEGIN characterizeAndFlatten")
-	defer debugln("END characterizeAndFlatten")
+// providers that run after invoke.  nonStaticTypes starts out as the
+// types provided by the invoke function.  initTypes are the types
+// provided by the init function.
+func (c Collection) characterizeAndFlatten(nonStaticTypes map[typeCode]bool, initTypes []typeCode, tr *tracer) ([]*provider, []*provider, error) {
+	tr.debugln("BEGIN characterizeAndFlatten")
+	defer tr.debugln("END characterizeAndFlatten")
 
 	afterInit := make([]*provider, 0, len(c.contents))
 	afterInvoke := make([]*provider, 0, len(c.contents))
 
-	err := c.handleReplaceByName()
+	c.removeDirectives(tr.inPhase(phaseReplace))
+
+	err := c.handleReplaceByName(tr.inPhase(phaseReplace))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = c.handleDefaults(tr.inPhase(phaseReplace))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chainInputs := make([]typeCode, 0, len(nonStaticTypes)+len(initTypes))
+	for tc := range nonStaticTypes {
+		chainInputs = append(chainInputs, tc)
+	}
+	chainInputs = append(chainInputs, initTypes...)
+	err = c.handleConversions(chainInputs, tr.inPhase(phaseReplace))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -242,6 +338,12 @@ func (c Collection) characterizeAndFlatten(nonStaticTypes map[typeCode]bool) ([]
 		c.reorderNonFinal()
 	}
 
+	invokeTypes := make(map[typeCode]bool, len(nonStaticTypes))
+	for tc := range nonStaticTypes {
+		invokeTypes[tc] = true
+	}
+
+	characterized := make([]*provider, 0, len(c.contents))
 	for ii, fm := range c.contents {
 		cc := charContext{
 			isLast:          ii == len(c.contents)-1,
@@ -265,6 +367,8 @@ func (c Collection) characterizeAndFlatten(nonStaticTypes map[typeCode]bool) ([]
 				}
 			}
 		}
+		tr.decision(fm, "characterize", fm.group)
+
 		//nolint:exhaustive // on purpose
 		switch fm.group {
 		case runGroup, invokeGroup:
@@ -273,6 +377,15 @@ func (c Collection) characterizeAndFlatten(nonStaticTypes map[typeCode]bool) ([]
 			}
 		}
 
+		characterized = append(characterized, fm)
+	}
+
+	err = demoteReorderFromStatic(characterized, invokeTypes, tr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, fm := range characterized {
 		//nolint:exhaustive // on purpose
 		switch fm.group {
 		case staticGroup, literalGroup:
@@ -286,6 +399,70 @@ func (c Collection) characterizeAndFlatten(nonStaticTypes map[typeCode]bool) ([]
 	return afterInit, afterInvoke, nil
 }
 
+// demoteReorderFromStatic moves Reorder providers out of the STATIC set
+// if they consume anything provided by the RUN set.  Since Reorder
+// providers can be moved, it does not matter where the RUN set provider
+// is: a Reorder provider could end up after it.  Demoting a provider
+// changes the RUN set so this repeats until nothing changes.  Providers
+// that are not marked Reorder are re-checked too, but only against
+// the RUN set providers that came before them.
+func demoteReorderFromStatic(funcs []*provider, invokeTypes map[typeCode]bool, tr *tracer) error {
+	if !hasReorder(funcs) {
+		return nil
+	}
+	for {
+		anywhere := make(map[typeCode]bool)
+		for tc := range invokeTypes {
+			anywhere[tc] = true
+		}
+		for _, fm := range funcs {
+			if fm.group == runGroup {
+				for _, out := range fm.flows[outputParams] {
+					anywhere[out] = true
+				}
+			}
+		}
+		before := make(map[typeCode]bool)
+		for tc := range invokeTypes {
+			before[tc] = true
+		}
+		var changed bool
+		for i, fm := range funcs {
+			if fm.group == staticGroup {
+				nonStatic := before
+				if fm.reorder {
+					nonStatic = anywhere
+				}
+				for _, in := range fm.flows[inputParams] {
+					if !nonStatic[in] {
+						continue
+					}
+					var err error
+					fm, err = characterizeFunc(fm, charContext{
+						isLast:          i == len(funcs)-1,
+						inputsAreStatic: false,
+					})
+					if err != nil {
+						return err
+					}
+					funcs[i] = fm
+					tr.decision(fm, "characterize", fm.group)
+					changed = true
+					break
+				}
+			}
+			if fm.group == runGroup {
+				for _, out := range fm.flows[outputParams] {
+					before[out] = true
+				}
+			}
+		}
+		if !changed {
+			return nil
+		}
+	}
+}
+
 func newCollection(name string, funcs ...any) *Collection {
 	var contents []*provider
 	for i, fn := range funcs {
@@ -295,16 +472,20 @@ func newCollection(name string, funcs ...any) *Collection {
 		switch v := fn.(type) {
 		case *Collection:
 			if v != nil {
-				contents = append(contents, v.contents...)
+				for _, fm := range v.contents {
+					contents = append(contents, fm.nest(name))
+				}
 			}
 		case Collection:
-			contents = append(contents, v.contents...)
+			for _, fm := range v.contents {
+				contents = append(contents, fm.nest(name))
+			}
 		case *provider:
 			if v != nil {
-				contents = append(contents, v.renameIfEmpty(i, name))
+				contents = append(contents, v.renameIfEmpty(i, name).nest(name))
 			}
 		case provider:
-			contents = append(contents, v.renameIfEmpty(i, name))
+			contents = append(contents, v.renameIfEmpty(i, name).nest(name))
 		default:
 			p := newProvider(fn, i, name)
 			switch fmt.Sprintf("%T", fn) {
@@ -349,6 +530,27 @@ func (fm *provider) renameIfEmpty(i int, name string) *provider {
 	return fm
 }
 
+// nest records that the provider is inside the collection name.
+// Providers that got their name from the collection are not
+// nested inside it.
+func (fm *provider) nest(name string) *provider {
+	if fm.origin == name && len(fm.parents) == 0 {
+		return fm
+	}
+	nfm := fm.copy()
+	nfm.parents = append([]string{name}, fm.parents...)
+	return nfm
+}
+
+// path is the name of the provider prefixed by the names of the
+// collections that it is inside, separated by slashes.
+func (fm *provider) path() string {
+	if len(fm.parents) == 0 {
+		return fm.origin
+	}
+	return strings.Join(fm.parents, "/") + "/" + fm.origin
+}
+
 func (fm *provider) flatten() []*provider {
 	return []*provider{fm}
 }
@@ -396,3 +598,32 @@ func (c Collection) reorderNonFinal() {
 		return
 	}
 }
+
+// removeDirectives drops the providers created by TraceTo,
+// BindInterface, WithMatchPolicy, and newNote: they only configure the
+// binding process and are not part of the chain.  Notes are recorded
+// in the trace.
+func (c *Collection) removeDirectives(tr *tracer) {
+	isDirective := func(fm *provider) bool {
+		return fm.traceTo != nil || fm.matchRule != nil || fm.note != ""
+	}
+	for _, fm := range c.contents {
+		if fm.note != "" {
+			tr.decision(fm, "note", fm.note)
+		}
+	}
+	for i, fm := range c.contents {
+		if !isDirective(fm) {
+			continue
+		}
+		contents := make([]*provider, i, len(c.contents)-1)
+		copy(contents, c.contents[:i])
+		for _, fm := range c.contents[i+1:] {
+			if !isDirective(fm) {
+				contents = append(contents, fm)
+			}
+		}
+		c.contents = contents
+		return
+	}
+}
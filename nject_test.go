@@ -115,6 +115,45 @@ func TestAnnotateRequired(t *testing.T) {
 	})
 }
 
+func TestAnnotateStrict(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var counter int
+		p := Strict(Provide("foo", func() { counter++ }))
+		require.IsType(t, &provider{}, p)
+		require.True(t, p.(*provider).strict)
+		require.True(t, p.(*provider).copy().strict)
+	})
+}
+
+func TestAnnotateAfterBefore(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		p := After("a", Before("b", After("c", Provide("foo", func() {}))))
+		require.IsType(t, &provider{}, p)
+		require.True(t, p.(*provider).reorder)
+		require.Equal(t, []string{"c", "a"}, p.(*provider).runAfter)
+		require.Equal(t, []string{"b"}, p.(*provider).runBefore)
+		require.Equal(t, []string{"c", "a"}, p.(*provider).copy().runAfter)
+		require.Equal(t, []string{"b"}, p.(*provider).copy().runBefore)
+	})
+}
+
+func TestProviderPath(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		c := Sequence("server",
+			Sequence("db",
+				Provide("open", func() s1 { return "" }),
+				func() s2 { return "" },
+			),
+			func() s3 { return "" },
+		)
+		require.Len(t, c.contents, 3)
+		require.Equal(t, "server/db/open", c.contents[0].path())
+		require.Equal(t, "server/db", c.contents[1].path())
+		require.Equal(t, "server", c.contents[2].path())
+		require.Contains(t, c.contents[1].String(), "server/db(1) [")
+	})
+}
+
 func TestAnnotateMustCache(t *testing.T) {
 	wrapTest(t, func(t *testing.T) {
 		var counter int
@@ -158,3 +197,26 @@ func TestAnnotateCallsinner(t *testing.T) {
 		require.True(t, p.(*provider).copy().callsInner)
 	})
 }
+
+func TestAnnotateExclusive(t *testing.T) {
+	stc := getTypeCode("foo")
+	wrapTest(t, func(t *testing.T) {
+		p := Exclusive[string](func() string { return "" })
+		require.IsType(t, &provider{}, p)
+		require.Contains(t, p.(*provider).exclusive, stc)
+		require.Contains(t, p.(*provider).copy().exclusive, stc)
+	})
+}
+
+func TestAnnotateDefaultOverride(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		d := Default(func() string { return "" })
+		require.IsType(t, &provider{}, d)
+		require.True(t, d.(*provider).isDefault)
+		require.True(t, d.(*provider).copy().isDefault)
+		o := Override(func() string { return "" })
+		require.IsType(t, &provider{}, o)
+		require.True(t, o.(*provider).isOverride)
+		require.True(t, o.(*provider).copy().isOverride)
+	})
+}
@@ -3,6 +3,7 @@ package nject
 import (
 	"container/heap"
 	"fmt"
+	"strings"
 )
 
 // Reorder annotates a provider to say that its position in the injection
@@ -14,14 +15,29 @@ import (
 // then the ordering among these re-orderable providers will be in their
 // original order with respect to each other.
 //
-// When reordering, only exact type matches are considered.  Reorder does
-// not play well with Loose().
+// When reordering, types are matched the same way as when deciding
+// which providers to include: a provider that consumes an interface will
+// be placed after a provider that is Loose for that interface.  Providers
+// of types that implement the interface but are not Loose for it will, if
+// possible, be placed after the consumer.  A provider that consumes a
+// type without providing it will, if possible, be placed after the
+// pass-through providers of that type so that it receives the value
+// that they modified.
 //
-// Functions marked reorder are currently inelligible for the STATIC set.
+// Functions marked Reorder can be in the STATIC set if they are
+// Cacheable (or MustCache) and none of their inputs are provided by
+// the RUN set.  Since a Reorder provider could be moved after any
+// provider, that is true regardless of where RUN set providers are
+// in the chain.  Reorder providers in the STATIC set stay in the
+// STATIC set when they are moved.
 //
 // Note: reordering will happen too late for UpFlows(), DownFlows(), and
 // GenerateFromInjectionChain() to correctly capture the final shape.
 //
+// Reordering is repeated, ignoring the providers that were excluded,
+// until the set of included providers stops changing.  If it does not
+// stop changing, Bind fails.  Entire Collections may be marked Reorder.
+//
 // Reorder should be considered experimental in the sense that the rules
 // for placement of such providers are likely to be adjusted as feedback
 // arrives.
@@ -31,6 +47,141 @@ func Reorder(fn any) Provider {
 	})
 }
 
+// After annotates a provider to say that it must run after the
+// providers named target (the name given with Provide()) even if
+// no data flows between them.  If target has a slash in it, it is
+// a path, as with ReplaceNamed.  This is useful for side-effect
+// providers, like metrics or auditing, that must run after
+// something like authentication without consuming its output.
+//
+// After implies Reorder: the annotated provider will be moved as
+// needed to satisfy the constraint.  If no provider is named target,
+// the injection chain is deemed invalid.  Constraints that contradict
+// each other, or the fixed order of providers that are not Reorder,
+// cause Bind to fail with an error that describes the cycle.
+//
+// After can be applied more than once and can be combined with
+// Before.
+func After(target string, fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		fm.reorder = true
+		fm.runAfter = append(fm.runAfter, target)
+	})
+}
+
+// Before annotates a provider to say that it must run before the
+// providers named target (the name given with Provide()) even if
+// no data flows between them.  Other than the direction, it is the
+// same as After.
+func Before(target string, fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		fm.reorder = true
+		fm.runBefore = append(fm.runBefore, target)
+	})
+}
+
+// orderingConstraints turns the After and Before annotations into
+// pairs of indexes into funcs.  The first member of each pair must
+// come after the second.  Contradictory constraints are an error.
+func orderingConstraints(funcs []*provider) ([][2]int, error) {
+	var pairs [][2]int
+	named := func(name string) []int {
+		var targets []int
+		for i, fm := range funcs {
+			if fm.isNamed(name) {
+				targets = append(targets, i)
+			}
+		}
+		return targets
+	}
+	for i, fm := range funcs {
+		for _, name := range fm.runAfter {
+			targets := named(name)
+			if len(targets) == 0 {
+				return nil, fm.errorf("cannot run after '%s', not in chain", name)
+			}
+			for _, j := range targets {
+				if j != i {
+					pairs = append(pairs, [2]int{i, j})
+				}
+			}
+		}
+		for _, name := range fm.runBefore {
+			targets := named(name)
+			if len(targets) == 0 {
+				return nil, fm.errorf("cannot run before '%s', not in chain", name)
+			}
+			for _, j := range targets {
+				if j != i {
+					pairs = append(pairs, [2]int{j, i})
+				}
+			}
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	// Look for a cycle among the constraints combined with the fixed
+	// order of the providers that cannot be reordered.
+	after := make([][]int, len(funcs))
+	for _, pair := range pairs {
+		after[pair[0]] = append(after[pair[0]], pair[1])
+	}
+	lastNoReorder := -1
+	for i, fm := range funcs {
+		if fm.reorder {
+			continue
+		}
+		if lastNoReorder != -1 {
+			after[i] = append(after[i], lastNoReorder)
+		}
+		lastNoReorder = i
+	}
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(funcs))
+	var path []int
+	var cycle []int
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		state[i] = visiting
+		path = append(path, i)
+		for _, j := range after[i] {
+			switch state[j] {
+			case visiting:
+				for k, p := range path {
+					if p == j {
+						cycle = append(append(cycle, path[k:]...), j)
+						break
+					}
+				}
+				return true
+			case unvisited:
+				if visit(j) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		return false
+	}
+	for i := range funcs {
+		if state[i] == unvisited && visit(i) {
+			steps := make([]string, 0, len(cycle)-1)
+			for k := 0; k < len(cycle)-1; k++ {
+				steps = append(steps, fmt.Sprintf("%s must run after %s", funcs[cycle[k]], funcs[cycle[k+1]]))
+			}
+			return nil, fmt.Errorf("contradictory ordering constraints form a cycle:\n\t%s", strings.Join(steps, "\n\t"))
+		}
+	}
+	return pairs, nil
+}
+
 // Reorder re-arranges an array of funcs with the functions that are
 // marked Reorder potentially moving to no positions within the array.
 //
@@ -54,12 +205,40 @@ func Reorder(fn any) Provider {
 //	as a return value in the up-chain.
 //
 
+// markReorderMoved sets fm.d.reorderMoved for Reorder providers that
+// have different providers before them than they did originally.
+func markReorderMoved(original []*provider, reordered []*provider) {
+	originalPosition := make(map[*provider]int, len(original))
+	for i, fm := range original {
+		originalPosition[fm] = i
+	}
+	for i, fm := range reordered {
+		if !fm.reorder {
+			continue
+		}
+		if originalPosition[fm] != i {
+			fm.d.reorderMoved = true
+			continue
+		}
+		for _, before := range reordered[:i] {
+			if originalPosition[before] > i {
+				fm.d.reorderMoved = true
+				break
+			}
+		}
+	}
+}
+
+// Providers in skip are placed but otherwise ignored: they do not provide
+// or consume anything.  This is used to re-do the reordering without
+// the providers that were excluded from the chain.
+//
 // generateCheckers must be called before reorder()
-func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
-	debugln("begin reorder ----------------------------------------------------------")
+func reorder(funcs []*provider, initF *provider, skip map[*provider]bool, rules *matchRules, tr *tracer) ([]*provider, error) {
+	tr.debugln("begin reorder ----------------------------------------------------------")
 	var someReorder bool
 	for i, fm := range funcs {
-		debugln("\tSTART", i, fm, fm.cannotInclude, fm.include)
+		tr.debugln("\tSTART", i, fm, fm.cannotInclude, fm.include)
 		if fm.reorder {
 			someReorder = true
 		}
@@ -68,10 +247,16 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 		return funcs, nil
 	}
 
+	constraints, err := orderingConstraints(funcs)
+	if err != nil {
+		return nil, err
+	}
+
 	availableDown := make(interfaceMap)
 	availableUp := make(interfaceMap)
 
 	provideByNotRequire := make(map[typeCode][]int)
+	passThrough := make(map[typeCode][]int)
 	if initF != nil {
 		for _, t := range noNoType(initF.flows[outputParams]) {
 			availableDown.Add(t, 0, initF)
@@ -80,29 +265,52 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 	}
 
 	lastStatic := -1
+	staticStart := -1
+	invokeIndex := -1
 	for i, fm := range funcs {
 		for j := flowType(0); j < lastFlowType; j++ {
 			fm.d.hasFlow[j] = has(fm.flows[j])
 		}
+		if fm.group == staticGroup && !fm.reorder {
+			lastStatic = i
+			if staticStart == -1 {
+				staticStart = i
+			}
+		}
+		switch fm.class {
+		case initFunc:
+			staticStart = i
+		case invokeFunc:
+			invokeIndex = i
+		}
+		if skip[fm] {
+			continue
+		}
 		for _, t := range noNoType(fm.flows[outputParams]) {
 			availableDown.Add(t, i, fm)
 		}
 		for _, t := range noNoType(fm.flows[returnParams]) {
 			availableUp.Add(t, i, fm)
 		}
-		if fm.group == staticGroup && !fm.reorder {
-			lastStatic = i
-		}
 	}
 
 	upTypes := make(map[typeCode]int)
 	downTypes := make(map[typeCode]int)
+	position := make(map[*provider]int, len(funcs))
+	for i, fm := range funcs {
+		position[fm] = i
+	}
 
 	counter := len(funcs) + 1
 	receviedNotReturned := make(map[typeCode][]int)
 	for i, fm := range funcs {
+		if skip[fm] {
+			continue
+		}
 		for _, t := range noNoType(fm.flows[outputParams]) {
-			if !fm.d.hasFlow[inputParams](t) {
+			if fm.d.hasFlow[inputParams](t) {
+				passThrough[t] = append(passThrough[t], i)
+			} else {
 				provideByNotRequire[t] = append(provideByNotRequire[t], i)
 			}
 		}
@@ -119,7 +327,7 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 		if i == -1 || j == -1 {
 			return
 		}
-		debugln("\t", i, "comes after", j, map[bool]string{
+		tr.debugln("\t", i, "comes after", j, map[bool]string{
 			false: "weak",
 			true:  "strong",
 		}[strong])
@@ -134,12 +342,20 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 	cannotReorder := make([]int, 0, len(funcs))
 	lastNoReorder := -1
 	for i, fm := range funcs {
-		if fm.reorder && fm.group == runGroup {
-			// All reorder functions must be after the end of the
-			// static set
-			aAfterB(true, i, lastStatic)
+		if fm.reorder {
+			switch fm.group {
+			case runGroup:
+				// All reorder functions in the run set must be after
+				// the end of the static set
+				aAfterB(true, i, lastStatic)
+			case staticGroup:
+				// Reorder functions in the static set must be after
+				// the init function and before the invoke function
+				aAfterB(true, i, staticStart)
+				aAfterB(true, invokeIndex, i)
+			}
 		}
-		debugln("\t", i, "is", fm)
+		tr.debugln("\t", i, "is", fm)
 		if !fm.reorder {
 			// providers that cannot be reordered will be forced out
 			// one after another
@@ -147,8 +363,11 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 			aAfterB(true, i, lastNoReorder)
 			lastNoReorder = i
 		}
+		if skip[fm] {
+			continue
+		}
 		for _, tRaw := range noNoType(fm.flows[inputParams]) {
-			t, _, err := availableDown.bestMatch(tRaw, "downflow")
+			t, _, err := availableDown.bestLooseMatch(tRaw, "downflow", rules)
 			if err != nil {
 				// we'll simply ignore the type since it cannot be provided
 				continue
@@ -157,7 +376,7 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 			if num, ok := downTypes[t]; ok {
 				aAfterB(true, i, num)
 			} else {
-				debugln("\tdowntype", counter, t)
+				tr.debugln("\tdowntype", counter, t)
 				downTypes[t] = counter
 				aAfterB(true, i, counter)
 				counter++
@@ -167,10 +386,25 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 			for _, j := range provideByNotRequire[t] {
 				aAfterB(false, i, j)
 			}
+			// If you take a T and don't provide one, then you SHOULD be after
+			// the providers that modify T.
+			if !fm.d.hasFlow[outputParams](t) {
+				for _, j := range passThrough[t] {
+					aAfterB(false, i, j)
+				}
+			}
+			// If you take an interface, then providers of types that implement
+			// the interface but aren't Loose for it SHOULD be after you so that they
+			// are not closer than the Loose provider.
+			for _, p := range availableDown.notLoose(tRaw) {
+				if j := position[p]; j != i {
+					aAfterB(false, j, i)
+				}
+			}
 		}
 
 		for _, tRaw := range noNoType(fm.flows[returnParams]) {
-			t, _, err := availableUp.bestMatch(tRaw, "upflow")
+			t, _, err := availableUp.bestLooseMatch(tRaw, "upflow", rules)
 			if err != nil {
 				// we'll simply ignore the type since it cannot be provided
 				continue
@@ -181,7 +415,7 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 			if num, ok := upTypes[t]; ok {
 				aAfterB(!consumptionOptional, i, num)
 			} else {
-				debugln("\tuptype", counter, t)
+				tr.debugln("\tuptype", counter, t)
 				upTypes[t] = counter
 				aAfterB(!consumptionOptional, i, counter)
 				counter++
@@ -194,6 +428,13 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 		}
 	}
 
+	for _, pair := range constraints {
+		if skip[funcs[pair[0]]] || skip[funcs[pair[1]]] {
+			continue
+		}
+		aAfterB(true, pair[0], pair[1])
+	}
+
 	nodes := make([]node, counter)
 	for i := range funcs {
 		nodes[i] = node{
@@ -203,7 +444,7 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 			weakAfter:  make(map[int]struct{}),
 		}
 	}
-	debugln("\tcounter:", len(funcs), counter)
+	tr.debugln("\tcounter:", len(funcs), counter)
 	for i := len(funcs); i < counter; i++ {
 		nodes[i] = node{
 			before: make(map[int]struct{}),
@@ -211,12 +452,12 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 		}
 	}
 	for _, pair := range strongPairs {
-		debugln("\tstrong", pair)
+		tr.debugln("\tstrong", pair)
 		nodes[pair[1]].before[pair[0]] = struct{}{}
 		nodes[pair[0]].after[pair[1]] = struct{}{}
 	}
 	for _, pair := range weakPairs {
-		debugln("\tweak", pair)
+		tr.debugln("\tweak", pair)
 		nodes[pair[1]].weakBefore[pair[0]] = struct{}{}
 		nodes[pair[0]].weakAfter[pair[1]] = struct{}{}
 	}
@@ -224,7 +465,7 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 		if _, ok := nodes[pair[0]].weakBefore[pair[1]]; !ok {
 			continue
 		}
-		debugln("\tremove mutual weak", pair)
+		tr.debugln("\tremove mutual weak", pair)
 		delete(nodes[pair[1]].weakBefore, pair[0])
 		delete(nodes[pair[0]].weakBefore, pair[0])
 		delete(nodes[pair[0]].weakAfter, pair[1])
@@ -239,7 +480,7 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 	if initF != nil {
 		for _, t := range noNoType(initF.flows[outputParams]) {
 			if num, ok := downTypes[t]; ok {
-				debugln("\trelease down for InitF", t)
+				tr.debugln("\trelease down for InitF", t)
 				push(unblocked, funcs, num)
 			}
 		}
@@ -254,16 +495,19 @@ func reorder(funcs []*provider, initF *provider) ([]*provider, error) {
 		reorderedFuncs: make([]*provider, 0, len(funcs)),
 		upTypes:        upTypes,
 		downTypes:      downTypes,
+		skip:           skip,
+		tr:             tr,
 	}
 	x.run()
-	debugln("\tfinal order ...")
+	tr.debugln("\tfinal order ...")
 	for i, fm := range x.reorderedFuncs {
-		debugln("\t\t", i, fm)
+		tr.debugln("\t\t", i, fm)
 	}
-	debugln("------------------")
+	tr.debugln("------------------")
 	if len(funcs) != len(x.reorderedFuncs) {
 		return nil, fmt.Errorf("internal error: count of funcs changed during reorder")
 	}
+	markReorderMoved(funcs, x.reorderedFuncs)
 	return x.reorderedFuncs, nil
 }
 
@@ -285,26 +529,28 @@ type topo struct {
 	reorderedFuncs []*provider
 	upTypes        map[typeCode]int
 	downTypes      map[typeCode]int
+	skip           map[*provider]bool
+	tr             *tracer
 }
 
 func (x *topo) release(n, i int) {
 	if n >= len(x.funcs) {
 		// types only have strong relationships
-		debugln("\treleased", n)
+		x.tr.debugln("\treleased", n)
 		push(x.unblocked, x.funcs, n)
 	} else {
 		delete(x.nodes[n].after, i)
 		delete(x.nodes[n].weakAfter, i)
 		if len(x.nodes[n].after) == 0 {
 			if len(x.nodes[n].weakAfter) == 0 {
-				debugln("\treleased", n)
+				x.tr.debugln("\treleased", n)
 				push(x.unblocked, x.funcs, n)
 			} else {
-				debugln("\treleased (weak)", n, x.nodes[n].weakAfter)
+				x.tr.debugln("\treleased (weak)", n, x.nodes[n].weakAfter)
 				push(x.weakBlocked, x.funcs, n)
 			}
 		} else {
-			debugln("\tcannot release", n, x.nodes[n].after)
+			x.tr.debugln("\tcannot release", n, x.nodes[n].after)
 		}
 	}
 }
@@ -332,7 +578,7 @@ func (x *topo) run() {
 			released := len(x.nodes[i].after) == 0
 			x.processOne(i, released)
 		} else {
-			debugln("\tall done")
+			x.tr.debugln("\tall done")
 			break
 		}
 	}
@@ -346,7 +592,7 @@ func (x *topo) run() {
 }
 
 func (x *topo) processOne(i int, release bool) {
-	debugln("\tpopped", i, release)
+	x.tr.debugln("\tpopped", i, release)
 	if x.done[i] {
 		return
 	}
@@ -360,25 +606,27 @@ func (x *topo) processOne(i int, release bool) {
 	fm := x.funcs[i]
 	x.reorderedFuncs = append(x.reorderedFuncs, fm)
 	if !release {
-		debugln("\texclude", fm)
+		x.tr.debugln("\texclude", fm)
 		return
 	}
 
 	x.releaseNode(i)
-	x.releaseProvider(i, fm)
+	if !x.skip[fm] {
+		x.releaseProvider(i, fm)
+	}
 }
 
 func (x *topo) releaseProvider(i int, fm *provider) {
-	debugln("\tinclude", fm)
+	x.tr.debugln("\tinclude", fm)
 	for _, t := range noNoType(fm.flows[outputParams]) {
 		if num, ok := x.downTypes[t]; ok {
-			debugln("\trelease down", t)
+			x.tr.debugln("\trelease down", t)
 			x.release(num, i)
 		}
 	}
 	for _, t := range noNoType(fm.flows[receivedParams]) {
 		if num, ok := x.upTypes[t]; ok {
-			debugln("\trelease up", t)
+			x.tr.debugln("\trelease up", t)
 			x.release(num, i)
 		}
 	}
@@ -1,6 +1,7 @@
 package nject
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -186,3 +187,216 @@ func TestReorderInOut(t *testing.T) {
 		t.Log(dd.Trace)
 	}
 }
+
+// TestReorderIgnoresExcluded has p0 as an alternative provider of R01 that
+// ends up excluded.  A single reorder pass places p2 before p1 because of p0.
+// A second pass, without p0, gets the decoration right.
+func TestReorderIgnoresExcluded(t *testing.T) {
+	t.Parallel()
+	var got R01
+	require.NoError(t, Run(t.Name(),
+		Reorder(Sequence("everything",
+			Provide("p0", func() R01 { return "p0" }),
+			Provide("p1", func() (R04, R01) { return "p1", "p1" }),
+			Provide("p2", func(r R01, _ R04) R01 { return "p2(" + r + ")" }),
+		)),
+		func(r R01) { got = r },
+	))
+	assert.Equal(t, R01("p2(p1)"), got)
+}
+
+// TestReorderNoFixedPoint has a chain where reordering oscillates: when
+// d is excluded, b is placed so that d is used, and when d is included,
+// b is placed so that d is not used.
+func TestReorderNoFixedPoint(t *testing.T) {
+	t.Parallel()
+	err := Run(t.Name(),
+		Provide("a", func() (R00, R03) { return "", "" }),
+		Provide("b", Reorder(func(_ R01, _ R03) (R00, R02) { return "", "" })),
+		Provide("c", Reorder(func() (R03, R01) { return "", "" })),
+		Provide("d", func(_ R03, _ R00) R03 { return "" }),
+		func(_ R00) {},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("did not reach a fixed point after %d passes", maxReorderPasses))
+}
+
+func TestReorderWholeCollection(t *testing.T) {
+	t.Parallel()
+	var got R03
+	require.NoError(t, Run(t.Name(),
+		Reorder(Sequence("backwards",
+			func(r R02) R03 { return R03(r) + "3" },
+			func(r R01) R02 { return R02(r) + "2" },
+			func(_ R09) R01 { return "never" },
+			func(r R00) R01 { return R01(r) + "1" },
+			func() R00 { return "0" },
+		)),
+		func(r R03) { got = r },
+	))
+	assert.Equal(t, R03("0123"), got)
+}
+
+type reorderNamer interface{ Name() string }
+
+type (
+	reorderLoose    struct{ n string }
+	reorderNotLoose struct{ n string }
+)
+
+func (r *reorderLoose) Name() string    { return r.n }
+func (r *reorderNotLoose) Name() string { return r.n }
+
+func TestReorderLoose(t *testing.T) {
+	t.Parallel()
+	var got R01
+	require.NoError(t, Run(t.Name(),
+		Reorder(func(n reorderNamer) R01 { return R01(n.Name()) }),
+		Loose[reorderNamer](func() *reorderLoose { return &reorderLoose{n: "loose"} }),
+		func(r R01) { got = r },
+	))
+	assert.Equal(t, R01("loose"), got)
+}
+
+func TestReorderLooseNotLooseCloser(t *testing.T) {
+	t.Parallel()
+	var got R01
+	require.NoError(t, Run(t.Name(),
+		Reorder(func(n reorderNamer) R01 { return R01(n.Name()) }),
+		Loose[reorderNamer](func() *reorderLoose { return &reorderLoose{n: "loose"} }),
+		func() *reorderNotLoose { return &reorderNotLoose{n: "not loose"} },
+		func(r R01, _ *reorderNotLoose) { got = r },
+	))
+	assert.Equal(t, R01("loose"), got)
+}
+
+func TestReorderLoosePassThrough(t *testing.T) {
+	t.Parallel()
+	var got R01
+	require.NoError(t, Run(t.Name(),
+		Reorder(func(n reorderNamer) R01 { return R01(n.Name()) }),
+		Loose[reorderNamer](func() *reorderLoose { return &reorderLoose{n: "loose"} }),
+		Loose[reorderNamer](func(r *reorderLoose) *reorderLoose { return &reorderLoose{n: r.n + "+"} }),
+		func(r R01, d *Debugging) {
+			got = r
+			assert.Contains(t, d.Trace, "reorder reached a fixed point")
+		},
+	))
+	assert.Equal(t, R01("loose+"), got)
+}
+
+func TestAfterNamed(t *testing.T) {
+	t.Parallel()
+	var order []string
+	require.NoError(t, Run(t.Name(),
+		Required(After("auth", func() { order = append(order, "metrics") })),
+		Required(Before("auth", func() { order = append(order, "setup") })),
+		Provide("auth", Required(func() { order = append(order, "auth") })),
+		func() { order = append(order, "final") },
+	))
+	assert.Equal(t, []string{"setup", "auth", "metrics", "final"}, order)
+}
+
+func TestAfterNamedPath(t *testing.T) {
+	t.Parallel()
+	var order []string
+	require.NoError(t, Run(t.Name(),
+		Required(After("second/auth", func() { order = append(order, "metrics") })),
+		Sequence("first", Provide("auth", Required(func() { order = append(order, "first") }))),
+		Sequence("second", Provide("auth", Required(func() { order = append(order, "second") }))),
+		Required(Before("/"+t.Name()+"/first/auth", func() { order = append(order, "setup") })),
+		func() { order = append(order, "final") },
+	))
+	assert.Equal(t, []string{"setup", "first", "second", "metrics", "final"}, order)
+}
+
+func TestAfterNamedMissing(t *testing.T) {
+	t.Parallel()
+	err := Run(t.Name(),
+		Required(After("auth", func() {})),
+		func() {},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot run after 'auth', not in chain")
+}
+
+func TestAfterNamedCycle(t *testing.T) {
+	t.Parallel()
+	err := Run(t.Name(),
+		Provide("a", Required(After("b", func() {}))),
+		Provide("b", Required(After("a", func() {}))),
+		func() {},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "contradictory ordering constraints form a cycle")
+	assert.Regexp(t, `a \[func\(\)\].* must run after .*b \[func\(\)\]`, err.Error())
+	assert.Regexp(t, `b \[func\(\)\].* must run after .*a \[func\(\)\]`, err.Error())
+}
+
+func TestAfterNamedCycleWithFixedOrder(t *testing.T) {
+	t.Parallel()
+	err := Run(t.Name(),
+		Provide("first", Required(func() {})),
+		Provide("middle", Required(After("second", Before("first", func() {})))),
+		Provide("second", Required(func() {})),
+		func() {},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "contradictory ordering constraints form a cycle")
+}
+
+func TestReorderStatic(t *testing.T) {
+	t.Parallel()
+	var staticCalls int
+	var got []string
+	var invoke func(R00)
+	var init func()
+	require.NoError(t, Sequence(t.Name(),
+		Cacheable(func(r1 R01) R02 { return R02(r1) }),
+		Reorder(Cacheable(func() R01 {
+			staticCalls++
+			return "-static"
+		})),
+		func(r0 R00, r2 R02) { got = append(got, string(r0)+string(r2)) },
+	).Bind(&invoke, &init))
+	init()
+	invoke("a")
+	invoke("b")
+	assert.Equal(t, []string{"a-static", "b-static"}, got)
+	assert.Equal(t, 1, staticCalls, "static Reorder provider runs once")
+}
+
+func TestReorderStaticDemoted(t *testing.T) {
+	t.Parallel()
+	var calls int
+	var got []string
+	var invoke func(R00)
+	var init func()
+	require.NoError(t, Sequence(t.Name(),
+		Reorder(Cacheable(func(r1 R01) R02 {
+			calls++
+			return R02(r1 + "-reordered")
+		})),
+		func(r0 R00) R01 { return R01(r0) },
+		func(r2 R02) { got = append(got, string(r2)) },
+	).Bind(&invoke, &init))
+	init()
+	invoke("a")
+	invoke("b")
+	assert.Equal(t, []string{"a-reordered", "b-reordered"}, got)
+	assert.Equal(t, 2, calls, "consumes from the RUN set so it is not static")
+}
+
+func TestReorderAfterPassThrough(t *testing.T) {
+	t.Parallel()
+	var got R02
+	require.NoError(t, Run(t.Name(),
+		Reorder(Sequence("backwards",
+			func(r R01) R02 { return R02(r) },
+			func(r R01) R01 { return r + "+" },
+			func() R01 { return "r" },
+		)),
+		func(r R02) { got = r },
+	))
+	assert.Equal(t, R02("r+"), got)
+}
@@ -2,6 +2,10 @@ package nject
 
 import (
 	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
 )
 
 // ReplaceNamed will edit the set of injectors, replacing target injector,
@@ -10,6 +14,13 @@ import (
 // This replacement happens very early in the
 // injection chain processing, before Reorder or injector selection.
 // If target does not exist, the injection chain is deemed invalid.
+//
+// If target has a slash in it, it is a path (see Provide) rather than
+// a name.  It matches injectors whose path ends with target, like
+// "db/open" for "server/db/open".  A target that starts with a slash
+// must match the entire path.  Paths can be used to pick out one
+// injector when names are duplicated.  The other replace, insert, and
+// remove directives accept paths too.
 func ReplaceNamed(target string, fn any) Provider {
 	return newThing(fn).modify(func(fm *provider) {
 		fm.replaceByName = target
@@ -40,6 +51,129 @@ func InsertBeforeNamed(target string, fn any) Provider {
 	})
 }
 
+// RemoveNamed will edit the set of injectors, removing the target
+// injector, identified by the name it was given with Provide().
+// RemoveNamed is not itself an injector: it is removed too.
+// This removal happens very early in the injection chain processing,
+// before Reorder or injector selection.
+// If target does not exist, the injection chain is deemed invalid.
+func RemoveNamed(target string) Provider {
+	fm := newProvider(func() {}, -1, "RemoveNamed")
+	fm.removeName = target
+	return fm
+}
+
+// Without creates a new collection that is the same as the
+// original collection except that the injectors with the given
+// names, as given with Provide(), are removed.  It is the same
+// as appending RemoveNamed directives for each name.
+// The original collection is not modified.
+func (c *Collection) Without(names ...string) *Collection {
+	removals := make([]any, len(names))
+	for i, name := range names {
+		removals[i] = RemoveNamed(name)
+	}
+	return c.Append(c.name, removals...)
+}
+
+// ReplaceMatching is like ReplaceNamed except that the target is
+// a pattern, as defined by path.Match, rather than a name.  The
+// pattern must match exactly one name.  Patterns with a slash
+// are matched against paths.
+func ReplaceMatching(pattern string, fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		fm.replaceMatching = pattern
+	})
+}
+
+// InsertAfterMatching is like InsertAfterNamed except that the target
+// is a pattern, as defined by path.Match, rather than a name.  The
+// pattern must match exactly one name.
+func InsertAfterMatching(pattern string, fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		fm.insertAfterMatch = pattern
+	})
+}
+
+// InsertBeforeMatching is like InsertBeforeNamed except that the target
+// is a pattern, as defined by path.Match, rather than a name.  The
+// pattern must match exactly one name.
+func InsertBeforeMatching(pattern string, fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		fm.insertBeforeMatch = pattern
+	})
+}
+
+// ReplaceProviderOf is like ReplaceNamed except that the target is
+// the provider that provides T.  Only that one provider is replaced,
+// even if it shares its name with other providers.  There must be
+// exactly one provider of T.  Providers that consume T as well as
+// providing it modify T and are not counted.  This is useful for replacing a provider
+// without knowing what it is named.
+func ReplaceProviderOf[T any](fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		fm.replaceProviderOf = getTypeCode(reflect.TypeOf((*T)(nil)).Elem())
+	})
+}
+
+// InsertAfterProviderOf is like InsertAfterNamed except that the
+// target is the provider that provides T.  There must be exactly
+// one provider of T.
+func InsertAfterProviderOf[T any](fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		fm.insertAfterOf = getTypeCode(reflect.TypeOf((*T)(nil)).Elem())
+	})
+}
+
+// InsertBeforeProviderOf is like InsertBeforeNamed except that the
+// target is the provider that provides T.  There must be exactly
+// one provider of T.
+func InsertBeforeProviderOf[T any](fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		fm.insertBeforeOf = getTypeCode(reflect.TypeOf((*T)(nil)).Elem())
+	})
+}
+
+// replaceDirective is one of the replace or insert annotations
+type replaceDirective struct {
+	op      string // "replace", "insert before", "insert after", or "remove"
+	name    string
+	pattern string
+	typ     typeCode
+}
+
+func (d replaceDirective) String() string {
+	switch {
+	case d.pattern != "":
+		return fmt.Sprintf("%s matching '%s'", d.op, d.pattern)
+	case d.typ != 0:
+		return fmt.Sprintf("%s provider of %s", d.op, d.typ)
+	default:
+		return fmt.Sprintf("%s '%s'", d.op, d.name)
+	}
+}
+
+func (fm *provider) replaceDirectives() []replaceDirective {
+	var directives []replaceDirective
+	for _, d := range []replaceDirective{
+		{op: "replace", name: fm.replaceByName},
+		{op: "replace", pattern: fm.replaceMatching},
+		{op: "replace", typ: fm.replaceProviderOf},
+		{op: "insert before", name: fm.insertBeforeName},
+		{op: "insert before", pattern: fm.insertBeforeMatch},
+		{op: "insert before", typ: fm.insertBeforeOf},
+		{op: "insert after", name: fm.insertAfterName},
+		{op: "insert after", pattern: fm.insertAfterMatch},
+		{op: "insert after", typ: fm.insertAfterOf},
+		{op: "remove", name: fm.removeName},
+	} {
+		if d.name != "" || d.pattern != "" || d.typ != 0 {
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
+
 // What makes handleReplaceByName complicated is that names can be duplicated
 // and the replace directives can be duplicated.
 //
@@ -48,23 +182,13 @@ func InsertBeforeNamed(target string, fn any) Provider {
 //
 // Likewise, when you tag a provider with InsertAfterName, you can
 // be tagging a collection, not an individual.
-func (c *Collection) handleReplaceByName() (err error) {
+func (c *Collection) handleReplaceByName(tr *tracer) (err error) {
 	defer func() {
-		if debugEnabled() {
-			debugln("replacment directives --------------------------------------")
+		if tr.enabled() {
+			tr.debugln("replacment directives --------------------------------------")
 			for _, fm := range c.contents {
-				var tag string
-				if fm.replaceByName != "" {
-					tag = "replace:" + fm.replaceByName
-				}
-				if fm.insertBeforeName != "" {
-					tag = "insertBefore:" + fm.insertBeforeName
-				}
-				if fm.insertAfterName != "" {
-					tag = "insertAfter:" + fm.insertAfterName
-				}
-				if tag != "" {
-					debugln("\t", tag, fm)
+				for _, d := range fm.replaceDirectives() {
+					tr.debugln("\t", d, fm)
 				}
 			}
 		}
@@ -72,7 +196,7 @@ func (c *Collection) handleReplaceByName() (err error) {
 
 	var hasReplacements bool
 	for _, fm := range c.contents {
-		if fm.replaceByName != "" || fm.insertAfterName != "" || fm.insertBeforeName != "" {
+		if len(fm.replaceDirectives()) != 0 {
 			hasReplacements = true
 			break
 		}
@@ -93,18 +217,8 @@ func (c *Collection) handleReplaceByName() (err error) {
 	head := &node{}
 	prior := head
 	for i, fm := range c.contents {
-		var replacers int
-		if fm.replaceByName != "" {
-			replacers++
-		}
-		if fm.insertBeforeName != "" {
-			replacers++
-		}
-		if fm.insertAfterName != "" {
-			replacers++
-		}
-		if replacers > 1 {
-			return fmt.Errorf("a provider, %s, can have only one of the ReplaceName, InsertAfterName, InsertBeforeName annotations", fm)
+		if len(fm.replaceDirectives()) > 1 {
+			return fmt.Errorf("a provider, %s, can have only one of the ReplaceName, InsertAfterName, InsertBeforeName, RemoveNamed annotations (including the Matching and ProviderOf variants)", fm)
 		}
 		n := &node{
 			i:    i,
@@ -117,45 +231,127 @@ func (c *Collection) handleReplaceByName() (err error) {
 	tail := &node{}
 	prior.next = tail
 
-	// step 2, build the name index
+	// step 2, target lookup.  Targets are found in the current list
+	// rather than an index so that earlier edits are accounted for.
 	type firstLast struct {
-		first      *node
-		last       *node
-		duplicated bool
+		first *node
+		last  *node
 	}
-	names := make(map[string]*firstLast)
-	var lastName string
-	var lastFirstLast *firstLast
-	for n := head.next; n != tail; n = n.next {
-		switch n.fm.origin {
-		case "":
-			// nothing to do
-			lastName = ""
-		case lastName:
-			lastFirstLast.last = n
-		default:
-			lastFirstLast = &firstLast{
-				first: n,
-				last:  n,
+	find := func(name string, op string, match func(*provider) bool) (*firstLast, error) {
+		var target *firstLast
+		for n := head.next; n != tail; n = n.next {
+			if n.fm.removeName != "" || !match(n.fm) {
+				// RemoveNamed directives are not targets
+				continue
 			}
-			lastName = n.fm.origin
-			if current, ok := names[lastName]; ok {
-				current.duplicated = true
-			} else {
-				names[lastName] = lastFirstLast
+			switch {
+			case target == nil:
+				target = &firstLast{first: n, last: n}
+			case target.last.next == n:
+				target.last = n
+			default:
+				return nil, fmt.Errorf("cannot %s '%s', duplicated in chain", op, name)
 			}
 		}
+		if target == nil {
+			return nil, fmt.Errorf("cannot %s '%s', not in chain", op, name)
+		}
+		return target, nil
 	}
 
+	// getTarget finds by name or, if the name has a slash in it,
+	// by path.
 	getTarget := func(name string, op string) (*firstLast, error) {
-		target, ok := names[name]
-		if !ok {
-			return nil, fmt.Errorf("cannot %s '%s', not in chain", op, name)
-		}
-		if target.duplicated {
-			return nil, fmt.Errorf("cannot %s '%s', duplicated in chain", op, name)
+		return find(name, op, func(fm *provider) bool { return fm.isNamed(name) })
+	}
+
+	// resolve finds the target of a directive.  It returns the name
+	// of the target if the target was found by name or pattern.
+	type flows struct {
+		inputs  map[typeCode]struct{}
+		outputs map[typeCode]struct{}
+	}
+	providerFlowCache := make(map[*provider]flows)
+	resolve := func(d replaceDirective) (*firstLast, string, error) {
+		switch {
+		case d.pattern != "":
+			// Patterns with a slash are matched against paths,
+			// others are matched against names.
+			byPath := strings.Contains(d.pattern, "/")
+			seen := make(map[string]struct{})
+			var matched []string
+			for n := head.next; n != tail; n = n.next {
+				candidate := n.fm.origin
+				if byPath {
+					candidate = n.fm.path()
+				}
+				if _, ok := seen[candidate]; ok || n.fm.removeName != "" || candidate == "" {
+					continue
+				}
+				seen[candidate] = struct{}{}
+				var ok bool
+				var err error
+				if byPath {
+					ok, err = pathMatches(d.pattern, candidate, path.Match)
+				} else {
+					ok, err = path.Match(d.pattern, candidate)
+				}
+				if err != nil {
+					return nil, "", fmt.Errorf("cannot %s: %w", d, err)
+				}
+				if ok {
+					matched = append(matched, candidate)
+				}
+			}
+			sort.Strings(matched)
+			switch len(matched) {
+			case 0:
+				return nil, "", fmt.Errorf("cannot %s, no names in chain match", d)
+			case 1:
+				name := matched[0]
+				if byPath {
+					name = "/" + name
+				}
+				target, err := getTarget(name, d.op)
+				return target, name, err
+			default:
+				return nil, "", fmt.Errorf("cannot %s, more than one name matches: '%s'", d, strings.Join(matched, "', '"))
+			}
+		case d.typ != 0:
+			var matched []*node
+			for n := head.next; n != tail; n = n.next {
+				if !n.processed && len(n.fm.replaceDirectives()) != 0 {
+					// directives that have not been applied yet
+					continue
+				}
+				f, ok := providerFlowCache[n.fm]
+				if !ok {
+					f.inputs, f.outputs = providerFlows(n.fm)
+					providerFlowCache[n.fm] = f
+				}
+				// providers that also consume T modify it rather than provide it
+				_, provides := f.outputs[d.typ]
+				_, modifies := f.inputs[d.typ]
+				if provides && !modifies {
+					matched = append(matched, n)
+				}
+			}
+			switch len(matched) {
+			case 0:
+				return nil, "", fmt.Errorf("cannot %s, not in chain", d)
+			case 1:
+				return &firstLast{first: matched[0], last: matched[0]}, "", nil
+			default:
+				providers := make([]string, len(matched))
+				for i, n := range matched {
+					providers[i] = n.fm.String()
+				}
+				return nil, "", fmt.Errorf("cannot %s, more than one provider matches:\n\t%s", d, strings.Join(providers, "\n\t"))
+			}
+		default:
+			target, err := getTarget(d.name, d.op)
+			return target, d.name, err
 		}
-		return target, nil
 	}
 
 	// step 3, do replacements
@@ -187,16 +383,41 @@ func (c *Collection) handleReplaceByName() (err error) {
 			// forward in the list
 			continue
 		}
-		switch {
-		case n.fm.replaceByName != "":
-			name := n.fm.replaceByName
-			firstLast, err := getTarget(name, "replace")
+		directives := n.fm.replaceDirectives()
+		if len(directives) == 0 {
+			continue
+		}
+		d := directives[0]
+		target, name, err := resolve(d)
+		if err != nil {
+			return err
+		}
+		for t := target.first; t != target.last.next; t = t.next {
+			err := checkSealed(d, n.fm, t.fm)
 			if err != nil {
 				return err
 			}
-			delete(names, name)
-			firstSnip, lastSnip := snip(firstLast.first, func(n *node) bool { return n.fm.origin == name })
-			firstMove, lastMove := snip(n, func(n *node) bool { return n.fm.replaceByName == name })
+		}
+		describe := func(name string, target *node) string {
+			if name != "" {
+				return name
+			}
+			return target.fm.String()
+		}
+		sameDirective := func(m *node) bool {
+			directives := m.fm.replaceDirectives()
+			return len(directives) == 1 && directives[0] == d
+		}
+		switch d.op {
+		case "remove":
+			firstSnip, lastSnip := snip(target.first, func(m *node) bool { return m.prev != target.last })
+			tr.debugln("RemoveNamed removing sequence from", firstSnip.i, firstSnip.fm, "through", lastSnip.i, lastSnip.fm)
+			tr.decision(firstSnip.fm, "remove", name)
+			firstMove, _ := snip(n, sameDirective)
+			n = firstMove.prev
+		case "replace":
+			firstSnip, lastSnip := snip(target.first, func(m *node) bool { return m.prev != target.last })
+			firstMove, lastMove := snip(n, sameDirective)
 			if lastSnip.next == firstMove {
 				// adjacent blocks, snip before move, hack a reconnect
 				lastSnip.next = lastMove.next
@@ -204,42 +425,33 @@ func (c *Collection) handleReplaceByName() (err error) {
 
 			if firstSnip == lastSnip {
 				if firstMove == lastMove {
-					debugln("ReplaceNamed replacing", firstSnip.i, firstSnip.fm, "with", firstMove.i, firstMove.fm)
+					tr.debugln("ReplaceNamed replacing", firstSnip.i, firstSnip.fm, "with", firstMove.i, firstMove.fm)
 				} else {
-					debugln("ReplaceNamed replacing", firstSnip.i, firstSnip.fm, "with sequence from", firstMove.i, firstMove.fm, "to", lastMove.i, lastMove.fm)
+					tr.debugln("ReplaceNamed replacing", firstSnip.i, firstSnip.fm, "with sequence from", firstMove.i, firstMove.fm, "to", lastMove.i, lastMove.fm)
 				}
 			} else {
 				if firstMove == lastMove {
-					debugln("ReplaceNamed replacing sequence from", firstSnip.i, firstSnip.fm, "through", lastSnip.i, lastSnip.fm, "with", firstMove.i, firstMove.fm)
+					tr.debugln("ReplaceNamed replacing sequence from", firstSnip.i, firstSnip.fm, "through", lastSnip.i, lastSnip.fm, "with", firstMove.i, firstMove.fm)
 				} else {
-					debugln("ReplaceNamed replacing sequence from", firstSnip.i, firstSnip.fm, "through", lastSnip.i, lastSnip.fm, "with sequence from", firstMove.i, firstMove.fm, "to", lastMove.i, lastMove.fm)
+					tr.debugln("ReplaceNamed replacing sequence from", firstSnip.i, firstSnip.fm, "through", lastSnip.i, lastSnip.fm, "with sequence from", firstMove.i, firstMove.fm, "to", lastMove.i, lastMove.fm)
 				}
 			}
+			tr.decision(firstMove.fm, "replace", describe(name, firstSnip))
 			afterLastMove := lastMove.next
 			insertBefore(lastSnip.next, firstMove, lastMove)
 			n = afterLastMove.prev
-		case n.fm.insertBeforeName != "":
-			name := n.fm.insertBeforeName
-			firstLast, err := getTarget(name, "insert before")
-			if err != nil {
-				return err
-			}
-			firstMove, lastMove := snip(n, func(n *node) bool { return n.fm.insertBeforeName == name })
+		case "insert before":
+			firstMove, lastMove := snip(n, sameDirective)
+			tr.decision(firstMove.fm, "insert", "before "+describe(name, target.first))
 			afterLastMove := lastMove.next
-			insertBefore(firstLast.first, firstMove, lastMove)
+			insertBefore(target.first, firstMove, lastMove)
 			n = afterLastMove.prev
-		case n.fm.insertAfterName != "":
-			name := n.fm.insertAfterName
-			firstLast, err := getTarget(name, "insert after")
-			if err != nil {
-				return err
-			}
-			firstMove, lastMove := snip(n, func(n *node) bool { return n.fm.insertAfterName == name })
+		case "insert after":
+			firstMove, lastMove := snip(n, sameDirective)
+			tr.decision(firstMove.fm, "insert", "after "+describe(name, target.last))
 			afterLastMove := lastMove.next
-			insertBefore(firstLast.last.next, firstMove, lastMove)
+			insertBefore(target.last.next, firstMove, lastMove)
 			n = afterLastMove.prev
-		default:
-			// nothing
 		}
 	}
 
@@ -251,3 +463,34 @@ func (c *Collection) handleReplaceByName() (err error) {
 	c.contents = contents
 	return nil
 }
+
+// isNamed reports if fm is the provider called name.  If name has a
+// slash in it, it is matched against the path of fm.
+func (fm *provider) isNamed(name string) bool {
+	if strings.Contains(name, "/") {
+		ok, _ := pathMatches(name, fm.path(), func(a, b string) (bool, error) { return a == b, nil })
+		return ok
+	}
+	return fm.origin == name
+}
+
+// pathMatches reports if the path p ends with the target.  Only whole
+// names are matched: "db/open" matches "server/db/open" but not
+// "server/userdb/open".  A target that starts with a slash must match
+// the entire path.
+func pathMatches(target string, p string, match func(string, string) (bool, error)) (bool, error) {
+	if strings.HasPrefix(target, "/") {
+		return match(target[1:], p)
+	}
+	for {
+		ok, err := match(target, p)
+		if ok || err != nil {
+			return ok, err
+		}
+		i := strings.IndexByte(p, '/')
+		if i == -1 {
+			return false, nil
+		}
+		p = p[i+1:]
+	}
+}
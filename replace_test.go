@@ -316,3 +316,281 @@ func TestReplace(t *testing.T) {
 		})
 	}
 }
+
+func TestReplaceMatching(t *testing.T) {
+	t.Parallel()
+	chain := func(extra ...any) *nject.Collection {
+		return nject.Sequence("library",
+			nject.Provide("db.open", func() string { return "real" }),
+			nject.Provide("cache.open", func() int { return 3 }),
+			nject.Sequence("extra", extra...),
+		)
+	}
+	cases := []struct {
+		name  string
+		op    nject.Provider
+		want  string
+		error string
+	}{
+		{
+			name: "replace",
+			op:   nject.ReplaceMatching("db.*", func() string { return "fake" }),
+			want: "fake 3",
+		},
+		{
+			name: "insert after",
+			op:   nject.InsertAfterMatching("db.*", func(s string) string { return s + "+after" }),
+			want: "real+after 3",
+		},
+		{
+			name: "insert before",
+			op:   nject.InsertBeforeMatching("cache.*", func(s string) string { return s + "+before" }),
+			want: "real+before 3",
+		},
+		{
+			name:  "no match",
+			op:    nject.ReplaceMatching("queue.*", func() string { return "fake" }),
+			error: "cannot replace matching 'queue.*', no names in chain match",
+		},
+		{
+			name:  "multiple matches",
+			op:    nject.ReplaceMatching("*.open", func() string { return "fake" }),
+			error: "cannot replace matching '*.open', more than one name matches: 'cache.open', 'db.open'",
+		},
+		{
+			name:  "bad pattern",
+			op:    nject.ReplaceMatching("[", func() string { return "fake" }),
+			error: "cannot replace matching '[': syntax error in pattern",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			err := nject.Run(t.Name(),
+				chain(tc.op),
+				func(s string, i int) {
+					got = s + " " + strconv.Itoa(i)
+				},
+			)
+			if tc.error != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+type replaceDB string
+
+func TestReplaceProviderOf(t *testing.T) {
+	t.Parallel()
+	// The library's providers share one name so ReplaceNamed would
+	// replace all of them.
+	library := nject.Sequence("library",
+		func() replaceDB { return "real" },
+		func() int { return 3 },
+	)
+	cases := []struct {
+		name      string
+		op        nject.Provider
+		extra     []any
+		want      string
+		error     string
+		errorAlso string
+	}{
+		{
+			name: "replace",
+			op:   nject.ReplaceProviderOf[replaceDB](func() replaceDB { return "fake" }),
+			want: "fake 3",
+		},
+		{
+			name: "insert after",
+			op:   nject.InsertAfterProviderOf[replaceDB](func(db replaceDB) replaceDB { return db + "+after" }),
+			want: "real+after 3",
+		},
+		{
+			name: "insert before",
+			op:   nject.InsertBeforeProviderOf[int](func(db replaceDB) replaceDB { return db + "+before" }),
+			want: "real+before 3",
+		},
+		{
+			name:  "modifiers do not count",
+			op:    nject.ReplaceProviderOf[replaceDB](func() replaceDB { return "fake" }),
+			extra: []any{func(db replaceDB) replaceDB { return db + "+modified" }},
+			want:  "fake+modified 3",
+		},
+		{
+			name:  "no provider",
+			op:    nject.ReplaceProviderOf[string](func() string { return "fake" }),
+			error: "cannot replace provider of string, not in chain",
+		},
+		{
+			name:      "multiple providers",
+			op:        nject.ReplaceProviderOf[replaceDB](func() replaceDB { return "fake" }),
+			extra:     []any{nject.Provide("other", func() replaceDB { return "other" })},
+			error:     "cannot replace provider of nject_test.replaceDB, more than one provider matches:\n\t",
+			errorAlso: "other [func() nject_test.replaceDB]",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			err := nject.Run(t.Name(),
+				library,
+				nject.Sequence("extra", tc.extra...),
+				tc.op,
+				func(db replaceDB, i int) {
+					got = string(db) + " " + strconv.Itoa(i)
+				},
+			)
+			if tc.error != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.error)
+				assert.Contains(t, err.Error(), tc.errorAlso)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRemoveNamed(t *testing.T) {
+	t.Parallel()
+	library := nject.Sequence("library",
+		nject.Provide("base", func() string { return "base" }),
+		nject.Provide("metrics", func(s string) string { return s + "+metrics" }),
+		nject.Provide("refresher", nject.Sequence("refresher",
+			func(s string) string { return s + "+refresh1" },
+			func(s string) string { return s + "+refresh2" },
+		)),
+	)
+	cases := []struct {
+		name  string
+		chain *nject.Collection
+		want  string
+		error string
+	}{
+		{
+			name:  "nothing removed",
+			chain: library,
+			want:  "base+metrics+refresh1+refresh2",
+		},
+		{
+			name:  "remove directive",
+			chain: nject.Sequence("c", library, nject.RemoveNamed("metrics")),
+			want:  "base+refresh1+refresh2",
+		},
+		{
+			name:  "remove directive before target",
+			chain: nject.Sequence("c", nject.RemoveNamed("metrics"), library),
+			want:  "base+refresh1+refresh2",
+		},
+		{
+			name:  "without",
+			chain: library.Without("metrics", "refresher"),
+			want:  "base",
+		},
+		{
+			name:  "without missing",
+			chain: library.Without("metrics", "exporter"),
+			error: "cannot remove 'exporter', not in chain",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			err := nject.Run(t.Name(),
+				tc.chain,
+				func(s string) { got = s },
+			)
+			if tc.error != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	// the original is not modified
+	var got string
+	require.NoError(t, nject.Run(t.Name(), library, func(s string) { got = s }))
+	assert.Equal(t, "base+metrics+refresh1+refresh2", got)
+}
+
+func TestReplacePath(t *testing.T) {
+	t.Parallel()
+	server := nject.Sequence("server",
+		nject.Sequence("db",
+			nject.Provide("open", func() string { return "db" }),
+		),
+		nject.Provide("config", func() int { return 7 }),
+		nject.Sequence("cache",
+			nject.Provide("open", func(s string) string { return s + "+cache" }),
+		),
+	)
+	cases := []struct {
+		name  string
+		op    nject.Provider
+		want  string
+		error string
+	}{
+		{
+			name:  "name is duplicated",
+			op:    nject.ReplaceNamed("open", func() string { return "fake" }),
+			error: "cannot replace 'open', duplicated in chain",
+		},
+		{
+			name: "path suffix",
+			op:   nject.ReplaceNamed("db/open", func() string { return "fake" }),
+			want: "fake+cache",
+		},
+		{
+			name: "exact path",
+			op:   nject.InsertAfterNamed("/root/server/db/open", func(s string) string { return s + "+after" }),
+			want: "db+after+cache",
+		},
+		{
+			name:  "exact path must be whole",
+			op:    nject.ReplaceNamed("/server/db/open", func() string { return "fake" }),
+			error: "cannot replace '/server/db/open', not in chain",
+		},
+		{
+			name:  "partial names do not match",
+			op:    nject.ReplaceNamed("b/open", func() string { return "fake" }),
+			error: "cannot replace 'b/open', not in chain",
+		},
+		{
+			name: "path pattern",
+			op:   nject.InsertBeforeMatching("c*/open", func(s string) string { return s + "+before" }),
+			want: "db+before+cache",
+		},
+		{
+			name:  "path pattern matches more than one",
+			op:    nject.ReplaceMatching("server/*/open", func() string { return "fake" }),
+			error: "more than one name matches: 'root/server/cache/open', 'root/server/db/open'",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			err := nject.Run("root",
+				server,
+				tc.op,
+				func(s string) { got = s },
+			)
+			if tc.error != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -362,13 +362,13 @@ func TestInjectorsIncluded(t *testing.T) {
 				assert.Equal(t, []string{
 					"static static-injector: Debugging [func() *nject.Debugging]",
 					"literal literal-value: run1(1) [nject.s0]",
-					"static static-injector: TBF(0) [func(nject.s0) nject.s1]",
-					"static static-injector: TBF(1) [func(nject.s1) nject.s2]",
-					"static static-injector: TBF(3) [func(nject.s2) nject.s5]",
+					"static static-injector: run1/TBF(0) [func(nject.s0) nject.s1]",
+					"static static-injector: run1/TBF(1) [func(nject.s1) nject.s2]",
+					"static static-injector: run1/TBF(3) [func(nject.s2) nject.s5]",
 					"invoke invoke-func: run1 invoke func [*func() error]",
-					"run fallible-injector: Run()error [func() nject.TerminalError]",
+					"run fallible-injector: run1/Run()error [func() nject.TerminalError]",
 					"final final-func: run1(3) [func(nject.s5, *nject.Debugging)]",
-				}, d.Included)
+				}, stripLocations(d.Included))
 			}))
 	})
 }
@@ -402,15 +402,15 @@ func TestInjectorsIncludeExclude(t *testing.T) {
 				assert.Equal(t, []string{
 					"INCLUDED: static static-injector: Debugging [func() *nject.Debugging] BECAUSE used by final-func: run1(3) [func(nject.s5, *nject.Debugging)] (required)",
 					"EXCLUDED: literal literal-value: run1(0) [nject.s3] BECAUSE not used by any remaining providers",
-					"INCLUDED: literal literal-value: run1(1) [nject.s0] BECAUSE used by static-injector: TBF(0) [func(nject.s0) nject.s1] (used by static-injector: TBF(1) [func(nject.s1) nject.s2] (used by static-injector: TBF(3) [func(nject.s2) nject.s5] (used by final-func: run1(3) [func(nject.s5, *nject.Debugging)] (required))))",
-					"INCLUDED: static static-injector: TBF(0) [func(nject.s0) nject.s1] BECAUSE used by static-injector: TBF(1) [func(nject.s1) nject.s2] (used by static-injector: TBF(3) [func(nject.s2) nject.s5] (used by final-func: run1(3) [func(nject.s5, *nject.Debugging)] (required)))",
-					"INCLUDED: static static-injector: TBF(1) [func(nject.s1) nject.s2] BECAUSE used by static-injector: TBF(3) [func(nject.s2) nject.s5] (used by final-func: run1(3) [func(nject.s5, *nject.Debugging)] (required))",
-					"EXCLUDED: static static-injector: TBF(2) [func(nject.s3) nject.s4] BECAUSE not used by any remaining providers",
-					"INCLUDED: static static-injector: TBF(3) [func(nject.s2) nject.s5] BECAUSE used by final-func: run1(3) [func(nject.s5, *nject.Debugging)] (required)",
+					"INCLUDED: literal literal-value: run1(1) [nject.s0] BECAUSE used by static-injector: run1/TBF(0) [func(nject.s0) nject.s1] (used by static-injector: run1/TBF(1) [func(nject.s1) nject.s2] (used by static-injector: run1/TBF(3) [func(nject.s2) nject.s5] (used by final-func: run1(3) [func(nject.s5, *nject.Debugging)] (required))))",
+					"INCLUDED: static static-injector: run1/TBF(0) [func(nject.s0) nject.s1] BECAUSE used by static-injector: run1/TBF(1) [func(nject.s1) nject.s2] (used by static-injector: run1/TBF(3) [func(nject.s2) nject.s5] (used by final-func: run1(3) [func(nject.s5, *nject.Debugging)] (required)))",
+					"INCLUDED: static static-injector: run1/TBF(1) [func(nject.s1) nject.s2] BECAUSE used by static-injector: run1/TBF(3) [func(nject.s2) nject.s5] (used by final-func: run1(3) [func(nject.s5, *nject.Debugging)] (required))",
+					"EXCLUDED: static static-injector: run1/TBF(2) [func(nject.s3) nject.s4] BECAUSE not used by any remaining providers",
+					"INCLUDED: static static-injector: run1/TBF(3) [func(nject.s2) nject.s5] BECAUSE used by final-func: run1(3) [func(nject.s5, *nject.Debugging)] (required)",
 					"INCLUDED: invoke invoke-func: run1 invoke func [*func() error] BECAUSE required",
-					"INCLUDED: run fallible-injector: Run()error [func() nject.TerminalError] BECAUSE auto-desired (injector with no outputs)",
+					"INCLUDED: run fallible-injector: run1/Run()error [func() nject.TerminalError] BECAUSE auto-desired (injector with no outputs)",
 					"INCLUDED: final final-func: run1(3) [func(nject.s5, *nject.Debugging)] BECAUSE required",
-				}, d.IncludeExclude)
+				}, stripLocations(d.IncludeExclude))
 			}))
 	})
 }
@@ -439,3 +439,48 @@ func TestInjectorsReproduce(t *testing.T) {
 			}))
 	})
 }
+
+func TestStrictIncluded(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var called bool
+		require.NoError(t, Run(t.Name(),
+			s0("s0 value"),
+			Strict(func(s s0) s1 { return s1(s) }),
+			func(_ s1) { called = true },
+		))
+		assert.True(t, called)
+	})
+}
+
+func TestStrictExcluded(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		err := Run(t.Name(),
+			s0("s0 value"),
+			Strict(Provide("unused-s2", func(s s0) s2 { return s2(s) })),
+			func(_ s0) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "strict provider was excluded: ")
+		assert.Contains(t, err.Error(), "unused-s2")
+		assert.Contains(t, err.Error(), "not used by any remaining providers")
+	})
+}
+
+func TestStrictCollection(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		err := Run(t.Name(),
+			s0("s0 value"),
+			Strict(Sequence("strict",
+				Provide("unused-s2", func(s s0) s2 { return s2(s) }),
+				Provide("unused-s3", func(s s0) s3 { return s3(s) }),
+				Provide("used-s4", func(s s0) s4 { return s4(s) }),
+			)),
+			func(_ s4) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 strict providers were excluded:")
+		assert.Contains(t, err.Error(), "unused-s2")
+		assert.Contains(t, err.Error(), "unused-s3")
+		assert.NotContains(t, err.Error(), "used-s4")
+	})
+}
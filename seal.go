@@ -0,0 +1,62 @@
+package nject
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+var sealCounter int32
+
+// sealedBy identifies a sealed collection
+type sealedBy struct {
+	id   int32
+	name string
+}
+
+// Seal creates a sealed copy of a collection.  The providers in a
+// sealed collection cannot be the targets of ReplaceNamed,
+// InsertBeforeNamed, InsertAfterNamed, or RemoveNamed (or their variants)
+// unless the directive is also inside the sealed collection.  Likewise,
+// a Default in a sealed collection can only be replaced by an Override
+// that is inside the sealed collection.  Attempts to
+// change a sealed collection from outside cause Bind to fail.
+//
+// Seal is for library authors who want to make sure that internals,
+// like a security check, are not swapped out.
+//
+// The original collection is not modified.
+func Seal(c *Collection) *Collection {
+	s := sealedBy{
+		id:   atomic.AddInt32(&sealCounter, 1),
+		name: c.name,
+	}
+	return c.modify(func(fm *provider) {
+		fm.sealed = append(fm.sealed[:len(fm.sealed):len(fm.sealed)], s)
+	}).(*Collection)
+}
+
+// Sealed is a shortcut for Seal(Sequence(name, providers...))
+func Sealed(name string, providers ...any) *Collection {
+	return Seal(Sequence(name, providers...))
+}
+
+// checkSealed returns an error if fm cannot do action to target
+// because target is sealed and fm is not in the same sealed
+// collection.
+func checkSealed(action fmt.Stringer, fm *provider, target *provider) error {
+	for _, s := range target.sealed {
+		if !fm.inSeal(s) {
+			return fmt.Errorf("cannot %s, %s is in sealed collection '%s' and %s is not", action, target, s.name, fm)
+		}
+	}
+	return nil
+}
+
+func (fm *provider) inSeal(s sealedBy) bool {
+	for _, other := range fm.sealed {
+		if other == s {
+			return true
+		}
+	}
+	return false
+}
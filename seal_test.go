@@ -0,0 +1,129 @@
+package nject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sealTestLibrary() *Collection {
+	return Sealed("library",
+		Provide("open", func() s1 { return "real" }),
+		Provide("check", func(s s1) s1 { return s + "+checked" }),
+		ReplaceNamed("open", Provide("inside", func() s1 { return "inside" })),
+	)
+}
+
+func TestSealedInsideDirective(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got s1
+		require.NoError(t, Run(t.Name(),
+			sealTestLibrary(),
+			func(s s1) { got = s },
+		))
+		assert.Equal(t, s1("inside+checked"), got)
+	})
+}
+
+func TestSealedOutsideDirectives(t *testing.T) {
+	cases := []struct {
+		name  string
+		op    Provider
+		error string
+	}{
+		{
+			name:  "replace",
+			op:    ReplaceNamed("check", func(s s1) s1 { return s }),
+			error: "cannot replace 'check', TestSealedOutsideDirectives/replace/library/check [func(nject.s1) nject.s1]",
+		},
+		{
+			name:  "insert before",
+			op:    InsertBeforeNamed("check", func(s s1) s1 { return s }),
+			error: "cannot insert before 'check'",
+		},
+		{
+			name:  "insert after",
+			op:    InsertAfterNamed("check", func(s s1) s1 { return s }),
+			error: "cannot insert after 'check'",
+		},
+		{
+			name:  "remove",
+			op:    RemoveNamed("check"),
+			error: "cannot remove 'check'",
+		},
+		{
+			name:  "by type",
+			op:    ReplaceProviderOf[s1](func() s1 { return "fake" }),
+			error: "cannot replace provider of nject/v2.s1",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Run(t.Name(),
+				sealTestLibrary(),
+				tc.op,
+				func(_ s1) {},
+			)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.error)
+			assert.Contains(t, err.Error(), "is in sealed collection 'library'")
+		})
+	}
+}
+
+func TestSealNested(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		inner := Sealed("inner",
+			Provide("open", func() s1 { return "real" }),
+		)
+		// a directive in the outer sealed collection is not inside
+		// the inner sealed collection
+		err := Run(t.Name(),
+			Sealed("outer",
+				inner,
+				ReplaceNamed("open", func() s1 { return "fake" }),
+			),
+			func(_ s1) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is in sealed collection 'inner'")
+
+		// the original collection is not modified by Seal
+		original := Sequence("original", Provide("open", func() s1 { return "real" }))
+		_ = Seal(original)
+		var got s1
+		require.NoError(t, Run(t.Name(),
+			original,
+			ReplaceNamed("open", func() s1 { return "fake" }),
+			func(s s1) { got = s },
+		))
+		assert.Equal(t, s1("fake"), got)
+	})
+}
+
+func TestSealedDefault(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		library := Sealed("library",
+			Default(func() s1 { return "secure" }),
+		)
+		err := Run(t.Name(),
+			library,
+			Override(func() s1 { return "bypassed" }),
+			func(_ s1) {},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot override nject/v2.s1")
+		assert.Contains(t, err.Error(), "is in sealed collection 'library'")
+
+		var got s1
+		require.NoError(t, Run(t.Name(),
+			Sealed("library",
+				Default(func() s1 { return "secure" }),
+				Override(func() s1 { return "inside" }),
+			),
+			func(s s1) { got = s },
+		))
+		assert.Equal(t, s1("inside"), got)
+	})
+}
@@ -0,0 +1,65 @@
+//go:build go1.21
+
+package nject
+
+import (
+	"context"
+	"log/slog"
+)
+
+// TraceTo is a directive that sends nject's internal decision trace for
+// any chain that includes it to logger.  The trace is the same one that
+// is available as Debugging.Trace, but each line is a separate log record
+// at slog.LevelDebug with structured attributes:
+//
+//	"phase": one of "replace", "characterize", "include", "reorder", "generate",
+//	         or "bind" for the overall binding process
+//	"provider": the provider the line is about (if any)
+//	"type": the types the line is about (if any)
+//	"decision": what was decided about the provider (if anything)
+//
+// Decisions include "replace", "insert", and "remove" from ReplaceNamed,
+// InsertBeforeNamed, InsertAfterNamed, RemoveNamed, and their variants;
+// "characterize" with the group the provider was placed in; "include"
+// or "exclude" with the reason for the final selection; and "generate"
+// with the kind of function generated for each included provider.
+//
+// Only the trace of binding is logged: invoking the chain does not log
+// anything, so injected values never end up in the log.
+//
+// TraceTo is not a provider: it does not become part of the injection chain.
+// Tracing is per-chain so only binds that include TraceTo are logged.
+func TraceTo(logger *slog.Logger) Provider {
+	fm := newProvider(func() {}, -1, "TraceTo")
+	fm.traceTo = &traceTarget{
+		enabled: func() bool {
+			return logger.Enabled(context.Background(), slog.LevelDebug)
+		},
+		event: func(event traceEvent) { logTraceEvent(logger, event) },
+	}
+	return fm
+}
+
+func logTraceEvent(logger *slog.Logger, event traceEvent) {
+	ctx := context.Background()
+	attrs := make([]slog.Attr, 0, 4)
+	attrs = append(attrs, slog.String("phase", string(event.phase)))
+	if event.provider != nil {
+		attrs = append(attrs, slog.String("provider", event.provider.String()))
+	}
+	switch len(event.types) {
+	case 0:
+	case 1:
+		attrs = append(attrs, slog.String("type", event.types[0].String()))
+	default:
+		types := make([]string, len(event.types))
+		for i, t := range event.types {
+			types[i] = t.String()
+		}
+		attrs = append(attrs, slog.Any("type", types))
+	}
+	if event.decision != "" {
+		attrs = append(attrs, slog.String("decision", event.decision))
+	}
+	logger.LogAttrs(ctx, slog.LevelDebug, event.message, attrs...)
+}
@@ -0,0 +1,100 @@
+//go:build go1.21
+
+package nject
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceTo(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	var called bool
+	require.NoError(t, Run(t.Name(),
+		TraceTo(logger),
+		s0("x"),
+		Provide("unused", func() s2 { return "" }),
+		Provide("convert", func(s s0) s1 { return s1(s) }),
+		InsertAfterNamed("convert", Provide("after-convert", func(_ s1) {})),
+		func(_ s1) { called = true },
+	))
+	assert.True(t, called)
+
+	phases := make(map[string]bool)
+	decisions := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		assert.Equal(t, "DEBUG", record["level"])
+		phase, _ := record["phase"].(string)
+		phases[phase] = true
+		if decision, ok := record["decision"].(string); ok {
+			provider, _ := record["provider"].(string)
+			require.NotEmpty(t, provider, "decisions are about providers: %s", line)
+			for _, name := range []string{"unused", "convert", "after-convert"} {
+				if strings.Contains(provider, "/"+name+" [") {
+					decisions[decision+" "+name] = phase
+				}
+			}
+		}
+	}
+	for _, phase := range []string{"bind", "replace", "characterize", "include", "reorder", "generate"} {
+		assert.True(t, phases[phase], "phase %s", phase)
+	}
+	assert.Equal(t, "replace", decisions["insert after-convert"])
+	assert.Equal(t, "characterize", decisions["characterize convert"])
+	assert.Equal(t, "include", decisions["include convert"])
+	assert.Equal(t, "include", decisions["exclude unused"])
+	assert.NotContains(t, buf.String(), "TraceTo [", "directive is not part of the chain")
+}
+
+func TestTraceToNotEnabled(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	require.NoError(t, Run(t.Name(),
+		TraceTo(logger),
+		s0("x"),
+		func(_ s0) {},
+	))
+	assert.Empty(t, buf.String())
+}
+
+func TestTraceToWithDebugging(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	var trace string
+	require.NoError(t, Run(t.Name(),
+		TraceTo(logger),
+		s0("x"),
+		func(_ s0, d *Debugging) { trace = d.Trace },
+	))
+	assert.NotContains(t, trace, "debugging already in progress")
+	assert.Contains(t, trace, "final set of functions")
+	assert.NotEmpty(t, buf.String())
+}
+
+func TestTraceToBindOnly(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	var invoke func(s0) s1
+	require.NoError(t, Sequence(t.Name(),
+		TraceTo(logger),
+		func(s s0) s1 { return s1(s) },
+	).Bind(&invoke, nil))
+	bound := buf.Len()
+	assert.NotZero(t, bound)
+	assert.Equal(t, s1("secret"), invoke("secret"))
+	assert.Equal(t, bound, buf.Len(), "invoking the chain is not traced")
+	assert.NotContains(t, buf.String(), "secret")
+}
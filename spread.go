@@ -0,0 +1,243 @@
+package nject
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Out can be embedded in a struct to mark it for spreading.  When a
+// provider returns a struct (or pointer to a struct) that embeds Out,
+// the struct is not itself provided.  Instead each of its exported
+// fields is provided separately.  See Spread.
+//
+//	type Clients struct {
+//		nject.Out
+//		DB    *sql.DB `nject:"mustconsume"`
+//		Cache *redis.Client
+//	}
+type Out struct{}
+
+var outType = reflect.TypeOf(Out{})
+
+// Spread annotates a provider that returns a struct (or pointer to a
+// struct) so that instead of providing the struct, it provides each
+// of the exported fields of the struct as separate values.  If the
+// provider returns more than one struct, the struct must embed Out.
+// Structs that embed Out are spread without Spread.
+//
+// A nil pointer provides zero values for all of the fields.  Two fields
+// may not have the same type.
+//
+// Like the outputs of any provider, fields do not have to be consumed.
+//
+// The "nject" struct tag controls how the fields are treated.  Options
+// are separated by commas:
+//
+// "-" & "skip": the field is not provided.
+//
+// "mustconsume": if the provider is included in the chain, the field
+// must be consumed (see MustConsume).
+//
+// "name=something": the name used for the field in error messages.
+//
+// Spread cannot be used with wrappers or Reflective providers.
+func Spread(fn any) Provider {
+	return newThing(fn).modify(func(fm *provider) {
+		if fm.fatal != nil {
+			return
+		}
+		err := fm.spread(true)
+		if err != nil {
+			fm.fatal = fmt.Errorf("Spread: %w", err)
+		}
+	})
+}
+
+// spreadOut is called on all new providers to spread structs that
+// embed Out.
+func (fm *provider) spreadOut() {
+	err := fm.spread(false)
+	if err != nil {
+		fm.fatal = fmt.Errorf("nject.Out: %w", err)
+	}
+}
+
+type spreadField struct {
+	name        string
+	index       []int
+	typ         reflect.Type
+	optional    bool
+	mustConsume bool
+}
+
+// spread replaces fm.fn with a function that provides the fields of
+// the struct that fm.fn returns.  If explicit is false, only structs that
+// embed Out are spread.
+func (fm *provider) spread(explicit bool) error {
+	if fm.spreadDone {
+		return nil
+	}
+	switch fm.fn.(type) {
+	case Reflective, generatedFromInjectionChain:
+		if explicit {
+			return fmt.Errorf("cannot be applied to %T", fm.fn)
+		}
+		return nil
+	}
+	v := reflect.ValueOf(fm.baseFn())
+	if !v.IsValid() || v.Type().Kind() != reflect.Func {
+		if explicit {
+			return fmt.Errorf("cannot be applied to %T, not a function", fm.fn)
+		}
+		return nil
+	}
+	ft := v.Type()
+	inputs := typesIn(ft)
+	outputs := typesOut(ft)
+	var marked, structs []int
+	for i, out := range outputs {
+		st := out
+		if st.Kind() == reflect.Ptr {
+			st = st.Elem()
+		}
+		if st.Kind() != reflect.Struct {
+			continue
+		}
+		structs = append(structs, i)
+		if f, ok := st.FieldByName(outType.Name()); ok && f.Anonymous && f.Type == outType {
+			marked = append(marked, i)
+		}
+	}
+	spreading := marked
+	if len(spreading) == 0 {
+		if !explicit {
+			return nil
+		}
+		switch len(structs) {
+		case 0:
+			return fmt.Errorf("%s does not return a struct", ft)
+		case 1:
+			spreading = structs
+		default:
+			return fmt.Errorf("%s returns more than one struct, mark the one to spread with nject.Out", ft)
+		}
+	}
+	if len(inputs) > 0 && inputs[0].Kind() == reflect.Func {
+		return fmt.Errorf("cannot spread the return values of wrapper %s", ft)
+	}
+	if ft.IsVariadic() {
+		return fmt.Errorf("cannot spread the return values of variadic function %s", ft)
+	}
+
+	fields := make(map[int][]spreadField)
+	seen := make(map[reflect.Type]spreadField)
+	for _, out := range outputs {
+		seen[out] = spreadField{name: "return value", typ: out}
+	}
+	var newOutputs []reflect.Type
+	for i, out := range outputs {
+		var isSpread bool
+		for _, j := range spreading {
+			if i == j {
+				isSpread = true
+			}
+		}
+		if !isSpread {
+			newOutputs = append(newOutputs, out)
+			continue
+		}
+		delete(seen, out)
+		list, err := structFields(out)
+		if err != nil {
+			return err
+		}
+		for _, field := range list {
+			if field.optional {
+				return fmt.Errorf("optional cannot be used on %s of %s", field.name, out)
+			}
+			if prior, ok := seen[field.typ]; ok {
+				return fmt.Errorf("%s and %s both have type %s", prior.name, field.name, field.typ)
+			}
+			seen[field.typ] = field
+			newOutputs = append(newOutputs, field.typ)
+			if !field.mustConsume {
+				continue
+			}
+			if fm.mustConsume == nil {
+				fm.mustConsume = make(map[typeCode]struct{})
+			}
+			fm.mustConsume[getTypeCode(field.typ)] = struct{}{}
+		}
+		fields[i] = list
+	}
+
+	fm.spreadDone = true
+	fm.replaceFn(reflect.MakeFunc(reflect.FuncOf(inputs, newOutputs, false),
+		func(args []reflect.Value) []reflect.Value {
+			out := v.Call(args)
+			newOut := make([]reflect.Value, 0, len(newOutputs))
+			for i, o := range out {
+				list, ok := fields[i]
+				if !ok {
+					newOut = append(newOut, o)
+					continue
+				}
+				if o.Kind() == reflect.Ptr {
+					if o.IsNil() {
+						for _, field := range list {
+							newOut = append(newOut, reflect.Zero(field.typ))
+						}
+						continue
+					}
+					o = o.Elem()
+				}
+				for _, field := range list {
+					newOut = append(newOut, o.FieldByIndex(field.index))
+				}
+			}
+			return newOut
+		}).Interface())
+	return nil
+}
+
+// spreadFields lists the fields of a struct (or pointer to struct) that
+// are spread
+func structFields(t reflect.Type) ([]spreadField, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var list []spreadField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || (f.Anonymous && (f.Type == outType || f.Type == inType)) {
+			continue
+		}
+		field := spreadField{
+			name:  "field " + f.Name,
+			index: f.Index,
+			typ:   f.Type,
+		}
+		var skip bool
+		if tag, ok := f.Tag.Lookup("nject"); ok {
+			for _, opt := range strings.Split(tag, ",") {
+				switch {
+				case opt == "-" || opt == "skip":
+					skip = true
+				case opt == "optional":
+					field.optional = true
+				case opt == "mustconsume":
+					field.mustConsume = true
+				case strings.HasPrefix(opt, "name="):
+					field.name = "field " + strings.TrimPrefix(opt, "name=")
+				default:
+					return nil, fmt.Errorf("unknown tag value '%s' on field %s of %s", opt, f.Name, t)
+				}
+			}
+		}
+		if !skip {
+			list = append(list, field)
+		}
+	}
+	return list, nil
+}
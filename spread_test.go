@@ -0,0 +1,156 @@
+package nject
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type spreadBundle struct {
+	S0      s0
+	S1      s1
+	S2      s2 `nject:"-"`
+	private s3
+}
+
+type spreadOutBundle struct {
+	Out
+	S0 s0
+	S1 *s1 `nject:"name=pointer"`
+}
+
+func TestSpread(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got0 s0
+		var got1 s1
+		require.NoError(t, Run(t.Name(),
+			Spread(func() spreadBundle { return spreadBundle{S0: "zero", S1: "one", S2: "two"} }),
+			func(a s0, b s1) { got0, got1 = a, b },
+		))
+		assert.Equal(t, s0("zero"), got0)
+		assert.Equal(t, s1("one"), got1)
+
+		require.NoError(t, Run(t.Name(),
+			Spread(func() *spreadBundle { return nil }),
+			func(a s0, b s1) { got0, got1 = a, b },
+		))
+		assert.Equal(t, s0(""), got0, "nil pointer spreads zero values")
+	})
+}
+
+func TestSpreadOut(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got s0
+		p := Provide("bundle", func(i int) (spreadOutBundle, string) {
+			return spreadOutBundle{S0: s0(fmt.Sprint(i))}, "extra"
+		})
+		inputs, outputs := p.DownFlows()
+		assert.Equal(t, "[int]", fmt.Sprint(inputs))
+		assert.Equal(t, "[nject.s0 *nject.s1 string]", fmt.Sprint(outputs))
+		require.NoError(t, Run(t.Name(),
+			7,
+			p,
+			func(a s0, _ string) { got = a },
+		))
+		assert.Equal(t, s0("7"), got)
+	})
+}
+
+func TestSpreadMustConsume(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		var got s1
+		require.NoError(t, Run(t.Name(),
+			Spread(func() spreadBundle { return spreadBundle{S1: "one"} }),
+			func(s s1) { got = s },
+		), "s0 does not need to be consumed")
+		assert.Equal(t, s1("one"), got)
+
+		type mustBundle struct {
+			S0 s0 `nject:"mustconsume"`
+			S1 s1
+		}
+		err := Run(t.Name(),
+			Spread(func() mustBundle { return mustBundle{} }),
+			func(_ s1) {},
+		)
+		require.Error(t, err, "s0 is not consumed")
+		assert.Contains(t, err.Error(), "no consumer for nject/v2.s0")
+	})
+}
+
+func TestSpreadMemoized(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		p := Memoize(func() spreadBundle { return spreadBundle{S0: "zero"} })
+		var got spreadBundle
+		require.NoError(t, Run(t.Name(),
+			p,
+			func(b spreadBundle) { got = b },
+		))
+		assert.Equal(t, s0("zero"), got.S0)
+
+		var got0 s0
+		require.NoError(t, Run(t.Name(),
+			Spread(p),
+			func(a s0) { got0 = a },
+		))
+		assert.Equal(t, s0("zero"), got0)
+	})
+}
+
+func TestSpreadErrors(t *testing.T) {
+	wrapTest(t, func(t *testing.T) {
+		cases := []struct {
+			p    Provider
+			want string
+		}{
+			{
+				p:    Spread(func() s0 { return "" }),
+				want: "Spread: func() nject.s0 does not return a struct",
+			},
+			{
+				p:    Spread(func() (spreadBundle, *spreadBundle) { return spreadBundle{}, nil }),
+				want: "returns more than one struct, mark the one to spread with nject.Out",
+			},
+			{
+				p:    Spread(func(inner func()) spreadBundle { inner(); return spreadBundle{} }),
+				want: "cannot spread the return values of wrapper",
+			},
+			{
+				p:    Spread(func() (spreadBundle, s0) { return spreadBundle{}, "" }),
+				want: "return value and field S0 both have type nject.s0",
+			},
+			{
+				p:    Provide("out", func() (spreadOutBundle, *s1) { return spreadOutBundle{}, nil }),
+				want: "nject.Out: return value and field pointer both have type *nject.s1",
+			},
+			{
+				p: Spread(func() struct {
+					S0 s0 `nject:"bogus"`
+				} {
+					return struct {
+						S0 s0 `nject:"bogus"`
+					}{}
+				}),
+				want: "unknown tag value 'bogus' on field S0",
+			},
+			{
+				p: Spread(func() struct {
+					S0 s0 `nject:"optional"`
+				} {
+					return struct {
+						S0 s0 `nject:"optional"`
+					}{}
+				}),
+				want: "Spread: optional cannot be used on field S0",
+			},
+		}
+		for _, tc := range cases {
+			err := Run(t.Name(), tc.p, func() {})
+			if assert.Error(t, err, tc.want) {
+				assert.Contains(t, err.Error(), tc.want)
+			}
+		}
+	})
+}
@@ -9,6 +9,7 @@ package nject
 
 import (
 	"reflect"
+	"time"
 )
 
 // TerminalError is a standard error interface.  For fallible injectors,
@@ -30,14 +31,17 @@ type TerminalError interface {
 }
 
 // Debugging is provided to help diagnose injection issues. *Debugging
-// is injected into every chain that consumes it.  Injecting debugging
-// into any change can slow down the processing of all other chains because
-// debugging is controlled with a global.
+// is injected into every chain that consumes it.  The trace is
+// collected separately for each chain so injecting debugging into
+// one chain does not slow down the processing of other chains.
 type Debugging struct {
 	// Included is a list of the providers included in the chain.
 	//
 	// The format is:
-	// "${groupName} ${className} ${providerNameShape}"
+	// "${groupName} ${className} ${providerNameShape} (${location})"
+	//
+	// The location says where the provider was registered (passed to
+	// Sequence, Provide, etc) and where its function was defined.
 	Included []string
 
 	// NamesIncluded is a list of the providers included in the chain.
@@ -47,8 +51,8 @@ type Debugging struct {
 
 	// IncludeExclude is a list of all of the providers supplied to
 	// create the chain.  Why each was included or not explained.
-	// "INCLUDED ${groupName} ${className} ${providerNameShape} BECAUSE ${whyProviderWasInclude}"
-	// "EXCLUDED ${groupName} ${className} ${providerNameShape} BECAUSE ${whyProviderWasExcluded}"
+	// "INCLUDED ${groupName} ${className} ${providerNameShape} (${location}) BECAUSE ${whyProviderWasInclude}"
+	// "EXCLUDED ${groupName} ${className} ${providerNameShape} (${location}) BECAUSE ${whyProviderWasExcluded}"
 	IncludeExclude []string
 
 	// Trace is an nject internal debugging trace that details the
@@ -121,6 +125,9 @@ var (
 
 	ignoreType = reflect.TypeOf((*ignore)(nil)).Elem()
 
+	envLookupType = reflect.TypeOf((*EnvLookup)(nil)).Elem()
+	durationType  = reflect.TypeOf(time.Duration(0))
+
 	emptyInterfaceType = reflect.TypeOf((*any)(nil)).Elem()
 
 	debuggingType   = reflect.TypeOf((**Debugging)(nil)).Elem()